@@ -0,0 +1,28 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+// setCommandFromFile reads opts.CommandFile and sets the command which runs the script with sh -c to opts.Args.
+// The script is returned so that it's exposed as JoinCommand instead of "sh -c ...".
+func setCommandFromFile(opts *option.ExecOptions) (string, error) {
+	if len(opts.Args) != 0 {
+		return "", errors.New("command-file and command arguments can't be used at the same time")
+	}
+	b, err := os.ReadFile(opts.CommandFile)
+	if err != nil {
+		return "", fmt.Errorf("read the command file %s: %w", opts.CommandFile, err)
+	}
+	script := strings.TrimSuffix(string(b), "\n")
+	if strings.TrimSpace(script) == "" {
+		return "", fmt.Errorf("the command file %s is empty", opts.CommandFile)
+	}
+	opts.Args = []string{"sh", "-c", script}
+	return script, nil
+}
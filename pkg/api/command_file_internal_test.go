@@ -0,0 +1,75 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func Test_setCommandFromFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.sh")
+	if err := os.WriteFile(script, []byte("echo foo |\n  grep foo\n"), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.sh")
+	if err := os.WriteFile(empty, []byte("\n"), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	data := []struct {
+		title   string
+		opts    *option.ExecOptions
+		exp     string
+		expArgs []string
+		isErr   bool
+	}{
+		{
+			title: "the script is run with sh -c",
+			opts: &option.ExecOptions{
+				CommandFile: script,
+			},
+			exp:     "echo foo |\n  grep foo",
+			expArgs: []string{"sh", "-c", "echo foo |\n  grep foo"},
+		},
+		{
+			title: "command arguments are also given",
+			opts: &option.ExecOptions{
+				CommandFile: script,
+				Args:        []string{"echo", "foo"},
+			},
+			isErr: true,
+		},
+		{
+			title: "the file doesn't exist",
+			opts: &option.ExecOptions{
+				CommandFile: filepath.Join(dir, "foo.sh"),
+			},
+			isErr: true,
+		},
+		{
+			title: "the file is empty",
+			opts: &option.ExecOptions{
+				CommandFile: empty,
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			s, err := setCommandFromFile(d.opts)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, s)
+			require.Equal(t, d.expArgs, d.opts.Args)
+		})
+	}
+}
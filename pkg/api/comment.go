@@ -2,9 +2,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"unicode/utf8"
 
+	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
 	"github.com/suzuki-shunsuke/github-comment/pkg/github"
 )
 
@@ -12,9 +19,19 @@ import (
 type GitHub interface {
 	CreateComment(ctx context.Context, cmt *github.Comment) error
 	ListComments(ctx context.Context, pr *github.PullRequest) ([]*github.IssueComment, error)
-	HideComment(ctx context.Context, nodeID string) error
+	HideComment(ctx context.Context, nodeID string) (bool, error)
+	DeleteComment(ctx context.Context, org, repo string, commentID int64) error
+	ListReviewThreads(ctx context.Context, pr *github.PullRequest) ([]*github.ReviewThread, error)
+	ResolveReviewThread(ctx context.Context, threadID string) error
+	AddReaction(ctx context.Context, org, repo string, commentID int64, content string) error
 	GetAuthenticatedUser(ctx context.Context) (string, error)
 	PRNumberWithSHA(ctx context.Context, owner, repo, sha string) (int, error)
+	PRInfo(ctx context.Context, owner, repo string, number int) (*github.PRInfo, error)
+	PostReviewComment(ctx context.Context, cmt *github.Comment) error
+	CreateSuggestionReview(ctx context.Context, org, repo string, prNumber int, body string, suggestions []*github.Suggestion) error
+	TeamExists(ctx context.Context, org, team string) (bool, error)
+	UserExists(ctx context.Context, login string) (bool, error)
+	CreateStatus(ctx context.Context, org, repo, sha, state, statusContext, description, targetURL string) error
 }
 
 type CommentController struct {
@@ -22,20 +39,307 @@ type CommentController struct {
 	Expr     Expr
 	Getenv   func(string) string
 	Platform Platform
+	// ValidateMentions is how to handle mentions to users and teams which don't exist
+	ValidateMentions string
+	// MetadataOut is a file path where the embedded metadata is written as JSON
+	MetadataOut string
+	// Version is github-comment's version
+	Version string
+	// RedactPatterns are regular expressions. Matched strings in the body are replaced with "***"
+	RedactPatterns []string
+	// CommentLimit is the max number of comments which github-comment posts to a pull request
+	CommentLimit int
+	// Cooldown throttles comments across processes. If it's nil, comments aren't throttled
+	Cooldown *Cooldown
+	// Summary records the link to the posted comment into the summary comment. If it's nil, the summary comment isn't updated
+	Summary *Summary
+	// ExtraMetadata is merged into the embedded metadata. Keys must not conflict with built-in keys
+	ExtraMetadata map[string]interface{}
+	// MetadataSchema is types of embedded variables. Values are coerced when the metadata is read
+	MetadataSchema map[string]string
 }
 
 func (ctrl *CommentController) Post(ctx context.Context, cmt *github.Comment, hiddenParam map[string]interface{}) error {
-	if err := ctrl.GitHub.CreateComment(ctx, cmt); err != nil {
-		return fmt.Errorf("send a comment: %w", err)
+	footer, err := ctrl.getFooter(cmt, hiddenParam)
+	if err != nil {
+		return err
+	}
+	if len(ctrl.RedactPatterns) != 0 {
+		// redact the body before the length is checked
+		body, err := redact(cmt.Body, ctrl.RedactPatterns)
+		if err != nil {
+			return err
+		}
+		cmt.Body = body
+		bodyForTooLong, err := redact(cmt.BodyForTooLong, ctrl.RedactPatterns)
+		if err != nil {
+			return err
+		}
+		cmt.BodyForTooLong = bodyForTooLong
+	}
+	suffix := footer + cmt.Debug + cmt.Metadata
+	if cmt.FitBody != nil && github.CommentLength(cmt.Body) > github.MaxCommentLength-github.CommentLength(suffix) {
+		// trim the command output to fit the remaining length budget
+		body, err := cmt.FitBody(github.MaxCommentLength - github.CommentLength(suffix))
+		if err != nil {
+			return err
+		}
+		if len(ctrl.RedactPatterns) != 0 {
+			body, err = redact(body, ctrl.RedactPatterns)
+			if err != nil {
+				return err
+			}
+		}
+		cmt.Body = body
+	}
+	cmt.Body = truncateBody(cmt.Body, cmt.TooLongStrategy, github.MaxCommentLength-github.CommentLength(suffix))
+	if ctrl.ValidateMentions != "" {
+		body := cmt.Body
+		if cmt.MentionBody != nil {
+			b, err := cmt.MentionBody()
+			if err != nil {
+				return err
+			}
+			body = b
+		}
+		if err := ctrl.validateMentions(ctx, body+footer); err != nil {
+			return err
+		}
+	}
+	cmt.Body += suffix
+	cmt.BodyForTooLong += suffix
+	if err := checkCommentLimit(ctx, ctrl.GitHub, cmt, ctrl.CommentLimit, ctrl.MetadataSchema); err != nil {
+		return err
+	}
+	if posted, err := ctrl.createComment(ctx, cmt); err != nil {
+		return err
+	} else if !posted {
+		return nil
+	}
+	if cmt.MinimizeOnCreate && cmt.NodeID != "" {
+		minimized, err := ctrl.GitHub.HideComment(ctx, cmt.NodeID)
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"node_id": cmt.NodeID,
+			}).Warn("minimize the posted comment")
+		} else if !minimized {
+			logrus.WithFields(logrus.Fields{
+				"node_id": cmt.NodeID,
+			}).Warn("the posted comment isn't minimized. The token may not have the permission to minimize it")
+		}
+	}
+	if cmt.ReplacedCommentID != 0 {
+		if err := ctrl.GitHub.DeleteComment(ctx, cmt.Org, cmt.Repo, cmt.ReplacedCommentID); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"comment_id": cmt.ReplacedCommentID,
+			}).Warn("delete the repinned comment")
+		}
+	}
+	if ctrl.MetadataOut != "" {
+		if err := ctrl.writeMetadata(cmt); err != nil {
+			return err
+		}
+	}
+	if ctrl.Summary != nil {
+		if err := ctrl.updateSummary(ctx, cmt); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"summary": ctrl.Summary.Key,
+			}).Warn("update the summary comment")
+		}
+	}
+	return nil
+}
+
+// createComment creates or updates the comment.
+// If the comment isn't posted because of the cooldown, the first returned value is false.
+func (ctrl *CommentController) createComment(ctx context.Context, cmt *github.Comment) (bool, error) {
+	post := func() error {
+		if cmt.Path != "" {
+			if err := ctrl.GitHub.PostReviewComment(ctx, cmt); err != nil {
+				return fmt.Errorf("send a review comment: %w", err)
+			}
+			return nil
+		}
+		if err := ctrl.GitHub.CreateComment(ctx, cmt); err != nil {
+			return fmt.Errorf("send a comment: %w", err)
+		}
+		return nil
+	}
+	if ctrl.Cooldown == nil {
+		return true, post()
+	}
+	return ctrl.Cooldown.Run(ctx, post)
+}
+
+// writeMetadata writes the embedded metadata of the posted comment to MetadataOut as JSON.
+func (ctrl *CommentController) writeMetadata(cmt *github.Comment) error {
+	data := map[string]interface{}{}
+	if !extractMetaFromComment(cmt.Metadata, &data, ctrl.MetadataSchema) {
+		logrus.WithFields(logrus.Fields{
+			"metadata_out": ctrl.MetadataOut,
+		}).Warn("the comment has no metadata, so metadata isn't written")
+		return nil
+	}
+	data["Version"] = ctrl.Version
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal the metadata as JSON: %w", err)
+	}
+	if err := os.WriteFile(ctrl.MetadataOut, append(b, '\n'), 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write the metadata to a file %s: %w", ctrl.MetadataOut, err)
 	}
 	return nil
 }
 
-func extractMetaFromComment(body string, data *map[string]interface{}) bool {
+const (
+	footerStart = "<!-- github-comment-footer-start -->"
+	footerEnd   = "<!-- github-comment-footer-end -->"
+)
+
+// getFooter joins footers whose conditions are matched.
+func (ctrl *CommentController) getFooter(cmt *github.Comment, param map[string]interface{}) (string, error) {
+	if param == nil {
+		param = map[string]interface{}{}
+	}
+	footer := ""
+	for _, ft := range cmt.Footers {
+		if ft.When != "" {
+			f, err := ctrl.Expr.Match(ft.When, param)
+			if err != nil {
+				return "", fmt.Errorf("test a footer's condition is matched: %w", err)
+			}
+			if !f {
+				continue
+			}
+		}
+		footer += "\n" + ft.Body
+	}
+	if footer == "" {
+		return "", nil
+	}
+	// footers are enclosed by markers so that they are removed when the body is appended to the comment
+	return "\n" + footerStart + footer + "\n" + footerEnd, nil
+}
+
+const truncatedMarker = "\n\n... (truncated because the comment is too long) ...\n\n"
+
+func validateTooLongStrategy(strategy string) error {
+	switch strategy {
+	case "", config.TooLongStrategyFallback, config.TooLongStrategyTruncateMiddle, config.TooLongStrategyTruncateTail:
+		return nil
+	}
+	return errors.New("invalid too_long_strategy: " + strategy)
+}
+
+// truncateBody trims body to fit the limit according to the strategy.
+// If the strategy isn't a truncate strategy, body is returned as is.
+// The limit is counted by UTF-16 code units like GitHub.
+func truncateBody(body, strategy string, limit int) string {
+	if github.CommentLength(body) <= limit {
+		return body
+	}
+	size := limit - github.CommentLength(truncatedMarker)
+	if size < 0 {
+		size = 0
+	}
+	switch strategy {
+	case config.TooLongStrategyTruncateTail:
+		return body[:github.PrefixIndex(body, size)] + truncatedMarker
+	case config.TooLongStrategyTruncateMiddle:
+		half := size / 2 //nolint:gomnd
+		return body[:github.PrefixIndex(body, half)] + truncatedMarker + body[github.SuffixIndex(body, half):]
+	default:
+		return body
+	}
+}
+
+// runeStartBefore returns the largest index which is less than or equal to idx and is the start of a rune.
+func runeStartBefore(s string, idx int) int {
+	for idx > 0 && !utf8.RuneStart(s[idx]) {
+		idx--
+	}
+	return idx
+}
+
+// runeStartAfter returns the smallest index which is greater than or equal to idx and is the start of a rune.
+func runeStartAfter(s string, idx int) int {
+	for idx < len(s) && !utf8.RuneStart(s[idx]) {
+		idx++
+	}
+	return idx
+}
+
+func renderFooters(renderer Renderer, footers []*config.Footer, templates map[string]string, params interface{}) ([]*github.Footer, error) {
+	if len(footers) == 0 {
+		return nil, nil
+	}
+	ret := make([]*github.Footer, len(footers))
+	for i, footer := range footers {
+		body, err := renderer.Render(footer.Template, templates, params)
+		if err != nil {
+			return nil, fmt.Errorf("render a footer template: %w", err)
+		}
+		ret[i] = &github.Footer{
+			When: footer.When,
+			Body: body,
+		}
+	}
+	return ret, nil
+}
+
+// extractMetaFromComment extracts the embedded metadata from the comment body.
+// Embedded variables are converted to types declared in schema.
+func extractMetaFromComment(body string, data *map[string]interface{}, schema map[string]string) bool {
 	f, _ := metadata.Extract(body, data)
+	if f {
+		coerceMetadata(*data, schema)
+	}
 	return f
 }
 
+// newCommentLookup returns the expr helper `comment(target)`.
+// The helper returns the metadata of the latest comment whose metadata's Target is target.
+// If no comment matches, the helper returns nil.
+func newCommentLookup(comments []*github.IssueComment, schema map[string]string) func(target string) map[string]interface{} {
+	metas := map[string]map[string]interface{}{}
+	for _, comment := range comments {
+		metadata := map[string]interface{}{}
+		if !extractMetaFromComment(comment.Body, &metadata, schema) {
+			continue
+		}
+		target, ok := metadata["Target"].(string)
+		if !ok || target == "" {
+			continue
+		}
+		metas[target] = metadata
+	}
+	return func(target string) map[string]interface{} {
+		return metas[target]
+	}
+}
+
+// newLazyCommentLookup returns the expr helper `comment(target)` like newCommentLookup.
+// Comments of the pull request are listed when the helper is called first, so the GitHub API isn't called if the helper isn't used.
+// If comments can't be listed, the helper returns nil.
+func newLazyCommentLookup(ctx context.Context, gh GitHub, pr *github.PullRequest, schema map[string]string) func(target string) map[string]interface{} {
+	var once sync.Once
+	var lookup func(target string) map[string]interface{}
+	return func(target string) map[string]interface{} {
+		once.Do(func() {
+			var comments []*github.IssueComment
+			if pr.PRNumber != 0 {
+				a, err := gh.ListComments(ctx, pr)
+				if err != nil {
+					logrus.WithError(err).Warn("list issue or pull request comments for comment(target)")
+				}
+				comments = a
+			}
+			lookup = newCommentLookup(comments, schema)
+		})
+		return lookup(target)
+	}
+}
+
 func (ctrl *CommentController) complementMetaData(data map[string]interface{}) {
 	if data == nil {
 		return
@@ -48,5 +352,28 @@ func (ctrl *CommentController) complementMetaData(data map[string]interface{}) {
 
 func (ctrl *CommentController) getEmbeddedComment(data map[string]interface{}) (string, error) {
 	ctrl.complementMetaData(data)
+	if err := mergeExtraMetadata(data, ctrl.ExtraMetadata); err != nil {
+		return "", err
+	}
 	return metadata.Convert(data) //nolint:wrapcheck
 }
+
+// excludeNoEmbed returns embedded variable names excluding names in noEmbed.
+// Variables in noEmbed can be used in templates but are never embedded in the metadata.
+func excludeNoEmbed(names, noEmbed []string) []string {
+	if len(noEmbed) == 0 {
+		return names
+	}
+	excluded := make(map[string]struct{}, len(noEmbed))
+	for _, name := range noEmbed {
+		excluded[name] = struct{}{}
+	}
+	ret := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := excluded[name]; ok {
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret
+}
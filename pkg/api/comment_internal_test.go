@@ -0,0 +1,138 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func TestCommentController_getFooter(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		cmt   *github.Comment
+		exp   string
+		isErr bool
+	}{
+		{
+			title: "no footer",
+			cmt:   &github.Comment{},
+			exp:   "",
+		},
+		{
+			title: "only matched footers are appended",
+			cmt: &github.Comment{
+				Footers: []*github.Footer{
+					{
+						Body: "always",
+					},
+					{
+						When: "false",
+						Body: "success",
+					},
+					{
+						When: "true",
+						Body: "failure",
+					},
+				},
+			},
+			exp: "\n<!-- github-comment-footer-start -->\nalways\nfailure\n<!-- github-comment-footer-end -->",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &CommentController{
+				Expr: &expr.Expr{},
+			}
+			footer, err := ctrl.getFooter(d.cmt, nil)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, footer)
+		})
+	}
+}
+
+func Test_truncateBody(t *testing.T) {
+	t.Parallel()
+	body := strings.Repeat("a", 100) + strings.Repeat("b", 100)
+	data := []struct {
+		title    string
+		body     string
+		strategy string
+		limit    int
+		exp      string
+	}{
+		{
+			title:    "not too long",
+			body:     "hello",
+			strategy: config.TooLongStrategyTruncateTail,
+			limit:    10,
+			exp:      "hello",
+		},
+		{
+			title:    "fallback",
+			body:     body,
+			strategy: config.TooLongStrategyFallback,
+			limit:    10,
+			exp:      body,
+		},
+		{
+			title:    "truncate_tail",
+			body:     body,
+			strategy: config.TooLongStrategyTruncateTail,
+			limit:    len(truncatedMarker) + 10,
+			exp:      strings.Repeat("a", 10) + truncatedMarker,
+		},
+		{
+			title:    "truncate_middle",
+			body:     body,
+			strategy: config.TooLongStrategyTruncateMiddle,
+			limit:    len(truncatedMarker) + 10,
+			exp:      strings.Repeat("a", 5) + truncatedMarker + strings.Repeat("b", 5),
+		},
+		{
+			title:    "multibyte characters aren't broken",
+			body:     strings.Repeat("あ", 100),
+			strategy: config.TooLongStrategyTruncateTail,
+			limit:    len(truncatedMarker) + 10,
+			exp:      strings.Repeat("あ", 10) + truncatedMarker,
+		},
+		{
+			title:    "an emoji is counted as two",
+			body:     strings.Repeat("😀", 100),
+			strategy: config.TooLongStrategyTruncateTail,
+			limit:    len(truncatedMarker) + 11,
+			exp:      strings.Repeat("😀", 5) + truncatedMarker,
+		},
+		{
+			title:    "emoji truncate_middle",
+			body:     strings.Repeat("😀", 100),
+			strategy: config.TooLongStrategyTruncateMiddle,
+			limit:    len(truncatedMarker) + 10,
+			exp:      strings.Repeat("😀", 2) + truncatedMarker + strings.Repeat("😀", 2),
+		},
+		{
+			title:    "emoji just fits the limit",
+			body:     strings.Repeat("😀", 5),
+			strategy: config.TooLongStrategyTruncateTail,
+			limit:    10,
+			exp:      strings.Repeat("😀", 5),
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, truncateBody(d.body, d.strategy, d.limit))
+		})
+	}
+}
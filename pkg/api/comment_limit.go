@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// DefaultMaxCommentsPerPR is the default max number of comments which github-comment posts to a pull request.
+// This protects pull requests from runaway loops which create many comments.
+const DefaultMaxCommentsPerPR = 100
+
+// getCommentLimit returns the max number of comments per pull request.
+// The command line option takes precedence over the configuration file.
+func getCommentLimit(optLimit, cfgLimit int) int {
+	if optLimit > 0 {
+		return optLimit
+	}
+	if cfgLimit > 0 {
+		return cfgLimit
+	}
+	return DefaultMaxCommentsPerPR
+}
+
+// checkCommentLimit returns an error if the number of existing github-comment's comments on the pull request reaches the limit.
+// Updating an existing comment isn't limited and minimized comments aren't counted.
+func checkCommentLimit(ctx context.Context, gh GitHub, cmt *github.Comment, limit int, schema map[string]string) error {
+	if cmt.CommentID != 0 || cmt.PRNumber == 0 || limit <= 0 {
+		return nil
+	}
+	login, err := gh.GetAuthenticatedUser(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("get an authenticated user")
+	}
+	comments, err := gh.ListComments(ctx, &github.PullRequest{
+		Org:      cmt.Org,
+		Repo:     cmt.Repo,
+		PRNumber: cmt.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list comments to check the max number of comments: %w", err)
+	}
+	cnt := 0
+	for _, comment := range comments {
+		if comment.IsMinimized {
+			continue
+		}
+		if isPrunedComment(comment, login, "", schema) {
+			cnt++
+		}
+	}
+	if cnt >= limit {
+		return fmt.Errorf("the number of comments posted by github-comment reaches the limit %d. Please check if conditions are configured correctly or raise the limit by --pr-comment-limit or max_comments_per_pr", limit)
+	}
+	return nil
+}
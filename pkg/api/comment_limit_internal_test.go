@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_checkCommentLimit(t *testing.T) {
+	t.Parallel()
+	const meta = "hello\n<!-- github-comment: {\"TemplateKey\":\"default\"} -->"
+	data := []struct {
+		title    string
+		comments []*github.IssueComment
+		cmt      *github.Comment
+		limit    int
+		isErr    bool
+	}{
+		{
+			title: "under the limit",
+			comments: []*github.IssueComment{
+				newFakeComment("bot", meta, false),
+			},
+			cmt:   &github.Comment{PRNumber: 1},
+			limit: 2,
+		},
+		{
+			title: "reach the limit",
+			comments: []*github.IssueComment{
+				newFakeComment("bot", meta, false),
+				newFakeComment("bot", meta, false),
+			},
+			cmt:   &github.Comment{PRNumber: 1},
+			limit: 2,
+			isErr: true,
+		},
+		{
+			title: "minimized comments aren't counted",
+			comments: []*github.IssueComment{
+				newFakeComment("bot", meta, false),
+				newFakeComment("bot", meta, true),
+			},
+			cmt:   &github.Comment{PRNumber: 1},
+			limit: 2,
+		},
+		{
+			title: "comments of other users and comments without metadata aren't counted",
+			comments: []*github.IssueComment{
+				newFakeComment("bot", meta, false),
+				newFakeComment("octocat", meta, false),
+				newFakeComment("bot", "hello", false),
+			},
+			cmt:   &github.Comment{PRNumber: 1},
+			limit: 2,
+		},
+		{
+			title: "updating a comment isn't limited",
+			comments: []*github.IssueComment{
+				newFakeComment("bot", meta, false),
+			},
+			cmt:   &github.Comment{PRNumber: 1, CommentID: 10},
+			limit: 1,
+		},
+	}
+	ctx := context.Background()
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{login: "bot", comments: d.comments}
+			err := checkCommentLimit(ctx, gh, d.cmt, d.limit, nil)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func Test_commentsCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fake := &fakeGitHub{
+		login: "bot",
+		comments: []*github.IssueComment{
+			newFakeComment("bot", "hello", false),
+		},
+	}
+	gh := newCommentsCache(fake)
+	require.Equal(t, gh, newCommentsCache(gh))
+	pr := &github.PullRequest{Org: "suzuki-shunsuke", Repo: "github-comment", PRNumber: 1}
+	for i := 0; i < 2; i++ {
+		comments, err := gh.ListComments(ctx, pr)
+		require.Nil(t, err)
+		require.Len(t, comments, 1)
+	}
+	// the comment limit check reuses the listed comments
+	require.Nil(t, checkCommentLimit(ctx, gh, &github.Comment{
+		Org: "suzuki-shunsuke", Repo: "github-comment", PRNumber: 1,
+	}, 1, nil))
+	require.Equal(t, 1, fake.listCalls)
+	// comments of other pull requests aren't shared
+	_, err := gh.ListComments(ctx, &github.PullRequest{Org: "suzuki-shunsuke", Repo: "github-comment", PRNumber: 2})
+	require.Nil(t, err)
+	require.Equal(t, 2, fake.listCalls)
+	// the cache is discarded when a comment is created
+	require.Nil(t, gh.CreateComment(ctx, &github.Comment{Org: "suzuki-shunsuke", Repo: "github-comment", PRNumber: 1}))
+	_, err = gh.ListComments(ctx, pr)
+	require.Nil(t, err)
+	require.Equal(t, 3, fake.listCalls)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// commentsCache caches comments of pull requests during a command so that
+// the update condition, the comment limit, idempotency, and hide share one listing.
+// The cache of a pull request is discarded when a comment is created or deleted.
+type commentsCache struct {
+	GitHub
+	mutex    sync.Mutex
+	comments map[string][]*github.IssueComment
+}
+
+func newCommentsCache(gh GitHub) GitHub {
+	if gh == nil {
+		return nil
+	}
+	if _, ok := gh.(*commentsCache); ok {
+		return gh
+	}
+	return &commentsCache{
+		GitHub:   gh,
+		comments: map[string][]*github.IssueComment{},
+	}
+}
+
+func commentsCacheKey(org, repo string, prNumber int) string {
+	return org + "/" + repo + "#" + strconv.Itoa(prNumber)
+}
+
+func (cache *commentsCache) ListComments(ctx context.Context, pr *github.PullRequest) ([]*github.IssueComment, error) {
+	key := commentsCacheKey(pr.Org, pr.Repo, pr.PRNumber)
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if comments, ok := cache.comments[key]; ok {
+		return comments, nil
+	}
+	comments, err := cache.GitHub.ListComments(ctx, pr)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	cache.comments[key] = comments
+	return comments, nil
+}
+
+func (cache *commentsCache) CreateComment(ctx context.Context, cmt *github.Comment) error {
+	cache.clear(commentsCacheKey(cmt.Org, cmt.Repo, cmt.PRNumber))
+	return cache.GitHub.CreateComment(ctx, cmt) //nolint:wrapcheck
+}
+
+func (cache *commentsCache) DeleteComment(ctx context.Context, org, repo string, commentID int64) error {
+	// the pull request number is unknown, so all caches are discarded
+	cache.mutex.Lock()
+	cache.comments = map[string][]*github.IssueComment{}
+	cache.mutex.Unlock()
+	return cache.GitHub.DeleteComment(ctx, org, repo, commentID) //nolint:wrapcheck
+}
+
+func (cache *commentsCache) clear(key string) {
+	cache.mutex.Lock()
+	delete(cache.comments, key)
+	cache.mutex.Unlock()
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cooldownLockRetryInterval = 100 * time.Millisecond
+	// cooldownStaleLock is the age of a lock file which is regarded as left by a crashed process
+	cooldownStaleLock = time.Minute
+	// cooldownLockTimeout is longer than cooldownStaleLock so that a waiting process can remove a stale lock file
+	cooldownLockTimeout = 2 * cooldownStaleLock
+)
+
+// Cooldown throttles comments across processes.
+// The time when a comment was last posted is recorded in File per Key.
+type Cooldown struct {
+	File   string
+	Window time.Duration
+	Key    string
+}
+
+// newCooldown returns Cooldown. If file is empty, nil is returned.
+func newCooldown(file string, window time.Duration, templateKey, target string) *Cooldown {
+	if file == "" {
+		return nil
+	}
+	return &Cooldown{
+		File:   file,
+		Window: window,
+		Key:    cooldownKey(templateKey, target),
+	}
+}
+
+// cooldownKey returns the key of the cooldown file from the template key and the target.
+func cooldownKey(templateKey, target string) string {
+	return templateKey + "/" + target
+}
+
+// Run calls post unless a comment with the same key was posted within the window.
+// The cooldown file is locked while post is called so that concurrent processes don't post comments at the same time.
+// The first returned value is false if post isn't called.
+func (cd *Cooldown) Run(ctx context.Context, post func() error) (bool, error) {
+	unlock, err := lockFile(ctx, cd.File+".lock")
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	times, err := readCooldownFile(cd.File)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if last, ok := times[cd.Key]; ok && now.Sub(last) < cd.Window {
+		logrus.WithFields(logrus.Fields{
+			"cooldown_file": cd.File,
+			"key":           cd.Key,
+			"last_posted":   last,
+		}).Info("skip posting a comment because a comment was posted within the cooldown")
+		return false, nil
+	}
+	if err := post(); err != nil {
+		return false, err
+	}
+	times[cd.Key] = now
+	if err := writeCooldownFile(cd.File, times); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// lockFile creates the lock file exclusively and returns the function to remove it.
+// os.O_EXCL is used instead of flock so that it works on any platforms.
+func lockFile(ctx context.Context, p string) (func(), error) {
+	timer := time.NewTimer(cooldownLockTimeout)
+	defer timer.Stop()
+	for {
+		f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644) //nolint:gomnd
+		if err == nil {
+			if err := f.Close(); err != nil {
+				logrus.WithError(err).Warn("close a lock file")
+			}
+			return func() {
+				if err := os.Remove(p); err != nil {
+					logrus.WithError(err).WithField("lock_file", p).Warn("remove a lock file")
+				}
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("create a lock file %s: %w", p, err)
+		}
+		if stat, err := os.Stat(p); err == nil && time.Since(stat.ModTime()) > cooldownStaleLock {
+			logrus.WithField("lock_file", p).Warn("remove a stale lock file")
+			_ = os.Remove(p)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for a lock file %s: %w", p, ctx.Err())
+		case <-timer.C:
+			return nil, fmt.Errorf("timeout to wait for a lock file %s", p)
+		case <-time.After(cooldownLockRetryInterval):
+		}
+	}
+}
+
+// readCooldownFile reads times when comments were last posted.
+// If the file doesn't exist, an empty map is returned.
+func readCooldownFile(p string) (map[string]time.Time, error) {
+	times := map[string]time.Time{}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return times, nil
+		}
+		return nil, fmt.Errorf("read a cooldown file %s: %w", p, err)
+	}
+	if err := json.Unmarshal(b, &times); err != nil {
+		return nil, fmt.Errorf("parse a cooldown file %s as JSON: %w", p, err)
+	}
+	return times, nil
+}
+
+func writeCooldownFile(p string, times map[string]time.Time) error {
+	b, err := json.Marshal(times)
+	if err != nil {
+		return fmt.Errorf("marshal the cooldown as JSON: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write a cooldown file %s: %w", p, err)
+	}
+	return nil
+}
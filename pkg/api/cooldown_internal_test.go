@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCooldown_Run(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cnt := 0
+	post := func() error {
+		cnt++
+		return nil
+	}
+	file := filepath.Join(t.TempDir(), "cooldown.json")
+	cd := &Cooldown{
+		File:   file,
+		Window: time.Hour,
+		Key:    cooldownKey("default", ""),
+	}
+	posted, err := cd.Run(ctx, post)
+	require.Nil(t, err)
+	require.True(t, posted)
+	posted, err = cd.Run(ctx, post)
+	require.Nil(t, err)
+	require.False(t, posted)
+	// the cooldown is recorded per key
+	other := &Cooldown{
+		File:   file,
+		Window: time.Hour,
+		Key:    cooldownKey("default", "foo"),
+	}
+	posted, err = other.Run(ctx, post)
+	require.Nil(t, err)
+	require.True(t, posted)
+	require.Equal(t, 2, cnt)
+}
+
+func Test_lockFile_stale(t *testing.T) {
+	t.Parallel()
+	require.True(t, cooldownLockTimeout > cooldownStaleLock)
+	p := filepath.Join(t.TempDir(), "cooldown.json.lock")
+	require.Nil(t, os.WriteFile(p, nil, 0o600))
+	old := time.Now().Add(-2 * cooldownStaleLock)
+	require.Nil(t, os.Chtimes(p, old, old))
+	unlock, err := lockFile(context.Background(), p)
+	require.Nil(t, err)
+	unlock()
+	_, err = os.Stat(p)
+	require.True(t, os.IsNotExist(err))
+}
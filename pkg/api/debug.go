@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	debugFooterStart = "<!-- github-comment-debug-start -->"
+	debugFooterEnd   = "<!-- github-comment-debug-end -->"
+)
+
+// runIDEnvs are environment variables of CI run ids.
+// The first non empty value is used.
+var runIDEnvs = []string{ //nolint:gochecknoglobals
+	"GITHUB_RUN_ID",
+	"CIRCLE_WORKFLOW_ID",
+	"CODEBUILD_BUILD_ID",
+	"DRONE_BUILD_NUMBER",
+	"BUILD_ID",
+	"BITBUCKET_BUILD_NUMBER",
+}
+
+type debugInfo struct {
+	TemplateKey string
+	// Config is the matched configuration
+	Config map[string]interface{}
+	Vars   map[string]interface{}
+	RunID  string
+}
+
+func getRunID(getenv func(string) string) string {
+	if getenv == nil {
+		return ""
+	}
+	for _, k := range runIDEnvs {
+		if v := getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// renderDebugFooter returns a collapsed section for troubleshooting.
+// The section is enclosed by HTML comments so that it's easy to strip.
+// The JSON is HTML-escaped, so the section never contains the embedded metadata.
+func renderDebugFooter(info *debugInfo) (string, error) {
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal debug information as JSON: %w", err)
+	}
+	return "\n" + debugFooterStart + "\n<details>\n<summary>github-comment debug</summary>\n\n```json\n" +
+		string(b) + "\n```\n\n</details>\n" + debugFooterEnd, nil
+}
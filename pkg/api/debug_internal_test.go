@@ -0,0 +1,24 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renderDebugFooter(t *testing.T) {
+	t.Parallel()
+	footer, err := renderDebugFooter(&debugInfo{
+		TemplateKey: "default",
+		Vars: map[string]interface{}{
+			"foo": `<!-- github-comment: {"TemplateKey":"foo"} -->`,
+		},
+		RunID: "100",
+	})
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(footer, "\n"+debugFooterStart))
+	require.True(t, strings.HasSuffix(footer, debugFooterEnd))
+	require.False(t, strings.Contains(footer, "<!-- github-comment:"))
+	require.True(t, strings.Contains(footer, `"RunID": "100"`))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
@@ -30,16 +31,45 @@ type ExecController struct {
 	Expr     Expr
 	Platform Platform
 	Config   *config.Config
+	// Version is github-comment's version
+	Version string
+	// PreCommentExecutor runs pre_comment_command.
+	// Unlike Executor, the output must not be written to the standard output and the standard error output
+	PreCommentExecutor Executor
 }
 
 func (ctrl *ExecController) Exec(ctx context.Context, opts *option.ExecOptions) error { //nolint:funlen,cyclop
+	ctrl.GitHub = newCommentsCache(ctrl.GitHub)
 	if ctrl.Platform != nil {
 		if err := ctrl.Platform.ComplementExec(opts); err != nil {
 			return fmt.Errorf("complement opts with CI built in environment variables: %w", err)
 		}
 	}
 
-	if opts.PRNumber == 0 && opts.SHA1 != "" {
+	cfg := ctrl.Config
+
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if opts.TemplateKeyFromGit {
+		key, err := templateKeyFromGit(ctx, cfg.TemplateKeyRules, ctrl.Getenv)
+		if err != nil {
+			return err
+		}
+		if key != "" {
+			opts.TemplateKey = key
+		}
+	}
+
+	// GitHub API isn't called if the repository is unknown.
+	// The command is run anyway and the error is returned by option.ValidateExec.
+	if opts.PRNumber == 0 && opts.SHA1 != "" && option.ValidateRepository(&opts.Options) == nil {
 		prNum, err := ctrl.GitHub.PRNumberWithSHA(ctx, opts.Org, opts.Repo, opts.SHA1)
 		if err != nil {
 			logrus.WithError(err).WithFields(logrus.Fields{
@@ -53,22 +83,64 @@ func (ctrl *ExecController) Exec(ctx context.Context, opts *option.ExecOptions)
 		}
 	}
 
-	cfg := ctrl.Config
+	var extraExecConfigs [][]*config.ExecConfig
+	if len(opts.ExtraTemplateKeys) != 0 {
+		// resolve exec configs of all template keys before running the command so that a missing template key fails fast
+		a, err := ctrl.getExtraExecConfigs(cfg, opts)
+		if err != nil {
+			return fmt.Errorf("get config: %w", err)
+		}
+		extraExecConfigs = a
+	}
 
-	if cfg.Base != nil {
-		if opts.Org == "" {
-			opts.Org = cfg.Base.Org
+	// the matrix is parsed before running the command so that the exit code of the command isn't lost by an invalid matrix
+	matrix, err := getMatrix(opts.MatrixJSON, ctrl.Getenv)
+	if err != nil {
+		return err
+	}
+
+	// variables are interpolated before running the command so that the exit code of the command isn't lost by an undefined variable
+	if cfg.Vars == nil {
+		cfg.Vars = make(map[string]interface{}, len(opts.Vars)+len(opts.StructuredVars))
+	}
+	for k, v := range opts.StructuredVars {
+		cfg.Vars[k] = v
+	}
+	for k, v := range opts.Vars {
+		cfg.Vars[k] = v
+	}
+	if err := interpolateVars(cfg.Vars, opts.AllowUndefinedVars); err != nil {
+		return fmt.Errorf("interpolate variables: %w", err)
+	}
+
+	// the state file is read before running the command so that the exit code of the command isn't lost by an invalid state file
+	var previous []*ExecState
+	if opts.StateFile != "" {
+		states, err := readExecStates(opts.StateFile)
+		if err != nil {
+			return err
 		}
-		if opts.Repo == "" {
-			opts.Repo = cfg.Base.Repo
+		previous = states
+	}
+
+	script := ""
+	if opts.CommandFile != "" {
+		a, err := setCommandFromFile(opts)
+		if err != nil {
+			return err
 		}
+		script = a
 	}
 
-	result, execErr := ctrl.Executor.Run(ctx, &execute.Params{
-		Cmd:   opts.Args[0],
-		Args:  opts.Args[1:],
-		Stdin: ctrl.Stdin,
-	})
+	// the command is stopped by signals which are forwarded by the executor instead of the cancellation of ctx,
+	// so that the command receives the signal only once
+	result, attempts, execErr := ctrl.runWithTimeout(withoutCancel(ctx), opts)
+	if result.Signal != nil {
+		// ctx may be cancelled by the signal, so the comment is posted with the partial output in a new deadline
+		c, cancel := context.WithTimeout(withoutCancel(ctx), signalPostTimeout)
+		defer cancel()
+		ctx = c
+	}
 
 	if opts.SkipComment {
 		if execErr != nil {
@@ -77,6 +149,14 @@ func (ctrl *ExecController) Exec(ctx context.Context, opts *option.ExecOptions)
 		return nil
 	}
 
+	// the command is run even if no pull request is found, and only the comment is skipped
+	if err := validateRequirePR(&opts.Options); err != nil {
+		if execErr != nil {
+			return ecerror.Wrap(execErr, result.ExitCode)
+		}
+		return err
+	}
+
 	execConfigs, err := ctrl.getExecConfigs(cfg, opts)
 	if err != nil {
 		return fmt.Errorf("get config: %w", err)
@@ -86,11 +166,11 @@ func (ctrl *ExecController) Exec(ctx context.Context, opts *option.ExecOptions)
 		return fmt.Errorf("validate command options: %w", err)
 	}
 
-	if cfg.Vars == nil {
-		cfg.Vars = make(map[string]interface{}, len(opts.Vars))
-	}
-	for k, v := range opts.Vars {
-		cfg.Vars[k] = v
+	target, derived := getTarget(opts.Target, opts.Vars, cfg.AutoTarget, ctrl.Getenv, matrix)
+	opts.Target = target
+	targetCondition := ""
+	if derived && target != "" {
+		targetCondition = targetUpdateCondition(target)
 	}
 
 	ci := ""
@@ -98,42 +178,180 @@ func (ctrl *ExecController) Exec(ctx context.Context, opts *option.ExecOptions)
 		ci = ctrl.Platform.CI()
 	}
 	joinCommand := strings.Join(opts.Args, " ")
+	if opts.CommandFile != "" {
+		joinCommand = script
+	}
+	if opts.Lang == "" {
+		opts.Lang = cfg.Lang
+	}
 	templates := template.GetTemplates(&template.ParamGetTemplates{
 		Templates:      cfg.Templates,
 		CI:             ci,
 		JoinCommand:    joinCommand,
 		CombinedOutput: result.CombinedOutput,
+		Lang:           opts.Lang,
 	})
-	if err := ctrl.post(ctx, execConfigs, &ExecCommentParams{
-		ExitCode:       result.ExitCode,
-		Command:        result.Cmd,
-		JoinCommand:    joinCommand,
-		Stdout:         result.Stdout,
-		Stderr:         result.Stderr,
-		CombinedOutput: result.CombinedOutput,
-		PRNumber:       opts.PRNumber,
-		Org:            opts.Org,
-		Repo:           opts.Repo,
-		SHA1:           opts.SHA1,
-		TemplateKey:    opts.TemplateKey,
-		Template:       opts.Template,
-		Vars:           cfg.Vars,
-	}, templates); err != nil {
+	cmtParams := &ExecCommentParams{
+		ExitCode:              result.ExitCode,
+		JoinCommand:           joinCommand,
+		Stdout:                result.Stdout,
+		Stderr:                result.Stderr,
+		CombinedOutput:        result.CombinedOutput,
+		PRNumber:              opts.PRNumber,
+		Org:                   opts.Org,
+		Repo:                  opts.Repo,
+		SHA1:                  opts.SHA1,
+		TemplateKey:           opts.TemplateKey,
+		Target:                opts.Target,
+		Template:              opts.Template,
+		Vars:                  cfg.Vars,
+		NoMetadata:            opts.NoMetadata,
+		ValidateMentions:      opts.ValidateMentions,
+		MetadataOut:           opts.MetadataOut,
+		Previous:              previous,
+		DebugFooter:           opts.DebugFooter,
+		MinimizeOnCreate:      opts.MinimizeOnCreate,
+		IdempotencyKey:        opts.IdempotencyKey,
+		PRCommentLimit:        opts.PRCommentLimit,
+		CooldownFile:          opts.CooldownFile,
+		PRBase:                opts.PRBase,
+		Cooldown:              opts.Cooldown,
+		Summary:               opts.Summary,
+		CollectMatchedConfigs: opts.CollectMatchedConfigs,
+		Matrix:                matrix,
+		DryRun:                opts.DryRun,
+		MergeVarsFromComment:  opts.MergeVarsFromComment,
+		UpdateCondition:       opts.UpdateCondition,
+		TargetUpdateCondition: targetCondition,
+		CommentLookup: newLazyCommentLookup(ctx, ctrl.GitHub, &github.PullRequest{
+			Org:      opts.Org,
+			Repo:     opts.Repo,
+			PRNumber: opts.PRNumber,
+		}, cfg.MetadataSchema),
+		Command: ExecCommand{
+			Cmd:      result.Cmd,
+			Attempts: attempts,
+		},
+	}
+	prInfoConfigs := execConfigs
+	if len(extraExecConfigs) != 0 {
+		// copy not to modify the exec configs of the configuration
+		prInfoConfigs = append([]*config.ExecConfig{}, execConfigs...)
+		for _, a := range extraExecConfigs {
+			prInfoConfigs = append(prInfoConfigs, a...)
+		}
+	}
+	ctrl.setPRInfo(ctx, prInfoConfigs, cmtParams)
+	var noMatchErr error
+	if opts.FailOnNoMatch && opts.Template == "" {
+		noMatchErr = ctrl.checkExecConfigMatched(execConfigs, cmtParams, extraExecConfigs, opts.ExtraTemplateKeys)
+	}
+	var session *sessionState
+	if opts.FirstFailureOnly && result.ExitCode != 0 {
+		s, err := readSessionState(opts.SessionFile)
+		if err != nil {
+			return ecerror.Wrap(err, result.ExitCode)
+		}
+		session = s
+	}
+	if session != nil && session.FailureCommented && !opts.AlwaysComment {
+		logrus.WithFields(logrus.Fields{
+			"session_file": opts.SessionFile,
+		}).Info("skip posting a comment because a failure has already been commented in this session")
+	} else {
+		posted, err := ctrl.post(ctx, execConfigs, cmtParams, templates)
+		if err != nil {
+			if !opts.Silent {
+				fmt.Fprintf(ctrl.Stderr, "github-comment error: %+v\n", err)
+			}
+		}
+		for i, key := range opts.ExtraTemplateKeys {
+			params := *cmtParams
+			params.TemplateKey = key
+			p, err := ctrl.post(ctx, extraExecConfigs[i], &params, templates)
+			if err != nil {
+				if !opts.Silent {
+					fmt.Fprintf(ctrl.Stderr, "github-comment error: %+v\n", err)
+				}
+			}
+			posted = posted || p
+		}
+		if posted && session != nil && !session.FailureCommented {
+			session.FailureCommented = true
+			if err := writeSessionState(opts.SessionFile, session); err != nil {
+				return ecerror.Wrap(err, result.ExitCode)
+			}
+		}
+	}
+	if err := ctrl.setStatus(ctx, execConfigs, cmtParams, templates); err != nil {
 		if !opts.Silent {
 			fmt.Fprintf(ctrl.Stderr, "github-comment error: %+v\n", err)
 		}
 	}
+	for i, key := range opts.ExtraTemplateKeys {
+		params := *cmtParams
+		params.TemplateKey = key
+		if err := ctrl.setStatus(ctx, extraExecConfigs[i], &params, templates); err != nil {
+			if !opts.Silent {
+				fmt.Fprintf(ctrl.Stderr, "github-comment error: %+v\n", err)
+			}
+		}
+	}
+	if opts.StateFile != "" {
+		if err := writeExecStates(opts.StateFile, append(previous, &ExecState{
+			ExitCode:       result.ExitCode,
+			Command:        result.Cmd,
+			JoinCommand:    joinCommand,
+			Stdout:         result.Stdout,
+			Stderr:         result.Stderr,
+			CombinedOutput: result.CombinedOutput,
+		})); err != nil {
+			return ecerror.Wrap(err, result.ExitCode)
+		}
+	}
 	if execErr != nil {
 		return ecerror.Wrap(execErr, result.ExitCode)
 	}
+	return noMatchErr
+}
+
+// checkExecConfigMatched returns an error if no exec config of a template key matches.
+// An error of evaluating conditions is ignored because it's reported when the comment is posted.
+func (ctrl *ExecController) checkExecConfigMatched(
+	execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams,
+	extraExecConfigs [][]*config.ExecConfig, extraTemplateKeys []string,
+) error {
+	if _, f, err := ctrl.getExecConfig(execConfigs, cmtParams); err == nil && !f {
+		return fmt.Errorf("no exec config matches: template key %s", cmtParams.TemplateKey)
+	}
+	for i, key := range extraTemplateKeys {
+		if _, f, err := ctrl.getExecConfig(extraExecConfigs[i], cmtParams); err == nil && !f {
+			return fmt.Errorf("no exec config matches: template key %s", key)
+		}
+	}
 	return nil
 }
 
+// ExecCommand is the command which exec runs.
+// It's printed as the command string in templates, so `{{.Command}}` keeps working.
+type ExecCommand struct {
+	// Cmd is the command string
+	Cmd string
+	// Attempts is the number of times the command was run
+	Attempts int
+	// Env is environment variables which are allowlisted by include_env
+	Env map[string]string
+}
+
+func (cmd ExecCommand) String() string {
+	return cmd.Cmd
+}
+
 type ExecCommentParams struct {
 	Stdout         string
 	Stderr         string
 	CombinedOutput string
-	Command        string
+	Command        ExecCommand
 	JoinCommand    string
 	ExitCode       int
 	// PRNumber is the pull request number where the comment is posted
@@ -145,8 +363,53 @@ type ExecCommentParams struct {
 	// SHA1 is the commit SHA1
 	SHA1        string
 	TemplateKey string
+	Target      string
 	Template    string
 	Vars        map[string]interface{}
+	// NoMetadata disables the embedded metadata
+	NoMetadata       bool
+	ValidateMentions string
+	MetadataOut      string
+	// Previous is results of previous runs which are read from the state file
+	Previous []*ExecState
+	// DebugFooter appends a collapsed section for troubleshooting
+	DebugFooter bool
+	// MinimizeOnCreate minimizes the comment immediately after the comment is posted
+	MinimizeOnCreate bool
+	// Commit is the size of the pull request. It's set only if when conditions refer it
+	Commit ExecCommit
+	// IdempotencyKey is embedded in the metadata. If a comment with the same key exists, the comment isn't posted
+	IdempotencyKey string
+	// PRCommentLimit is the max number of comments which github-comment posts to a pull request
+	PRCommentLimit int
+	// PRBase is glob patterns of base branches. Comments are posted only on pull requests targeting the branches
+	PRBase []string
+	// CooldownFile and Cooldown throttle comments across processes
+	CooldownFile string
+	Cooldown     time.Duration
+	// Summary is the key of the summary comment which links the posted comment
+	Summary string
+	// Matrix is the matrix context of GitHub Actions
+	Matrix interface{}
+	// CollectMatchedConfigs collects all matched exec configs into MatchedConfigs
+	CollectMatchedConfigs bool
+	// MatchedConfigs is names of all matched exec configs.
+	// It's set only if CollectMatchedConfigs is true
+	MatchedConfigs []string
+	// DryRun skips side effects such as pre_comment_command
+	DryRun bool
+	// MergeVarsFromComment merges the embedded Vars of the previous comment into Vars
+	MergeVarsFromComment bool
+	// UpdateCondition is the update condition of the command line option
+	UpdateCondition string
+	// TargetUpdateCondition is the update condition which matches comments with the target derived by auto_target.
+	// It's empty if the target isn't derived
+	TargetUpdateCondition string
+	// CommentLookup is the expr helper `comment(target)` which returns the metadata of the comment with the target
+	CommentLookup func(target string) map[string]interface{} `expr:"comment" json:"-"`
+	// CommentExists is true if a comment matching the update condition of the command line option
+	// or default_update_condition exists. It's referred as commentExists in when
+	CommentExists bool `expr:"commentExists"`
 }
 
 type Executor interface {
@@ -166,16 +429,19 @@ func (ctrl *ExecController) getExecConfigs(cfg *config.Config, opts *option.Exec
 			if opts.TemplateKey != "default" {
 				return nil, errors.New("template isn't found: " + opts.TemplateKey)
 			}
-			execConfigs = []*config.ExecConfig{
-				{
-					When: "ExitCode != 0",
-					Template: `{{template "status" .}} {{template "link" .}}
-
-{{template "join_command" .}}
-
-{{template "hidden_combined_output" .}}`,
-				},
+			tpl, err := defaultExecTemplate(cfg.Delims)
+			if err != nil {
+				return nil, err
+			}
+			execConfig := &config.ExecConfig{
+				When:     "ExitCode != 0",
+				Template: tpl,
 			}
+			if cfg.DefaultExec != nil {
+				execConfig.MaxCombinedOutputSize = cfg.DefaultExec.MaxOutputSize
+				execConfig.CombinedOutputKeep = cfg.DefaultExec.OutputKeep
+			}
+			execConfigs = []*config.ExecConfig{execConfig}
 		} else {
 			execConfigs = a
 		}
@@ -183,15 +449,53 @@ func (ctrl *ExecController) getExecConfigs(cfg *config.Config, opts *option.Exec
 	return execConfigs, nil
 }
 
+// defaultExecTemplate returns the comment template which is used if the template key "default" isn't configured.
+// The template is written in the given delimiters because the comment template is parsed with them.
+func defaultExecTemplate(delims string) (string, error) {
+	tpl := `{{template "status" .}} {{template "link" .}}
+
+{{template "join_command" .}}
+
+{{template "hidden_combined_output" .}}`
+	if delims == "" {
+		return tpl, nil
+	}
+	left, right, err := template.ParseDelims(delims)
+	if err != nil {
+		return "", fmt.Errorf("parse template delimiters: %w", err)
+	}
+	return template.ReplaceDelims(tpl, left, right), nil
+}
+
+// getExtraExecConfigs returns exec configs of opts.ExtraTemplateKeys.
+// The index of the returned value corresponds to the index of opts.ExtraTemplateKeys.
+// opts.TemplateKey is also validated so that an error is returned if any template key isn't found.
+func (ctrl *ExecController) getExtraExecConfigs(cfg *config.Config, opts *option.ExecOptions) ([][]*config.ExecConfig, error) {
+	if _, err := ctrl.getExecConfigs(cfg, opts); err != nil {
+		return nil, err
+	}
+	ret := make([][]*config.ExecConfig, len(opts.ExtraTemplateKeys))
+	for i, key := range opts.ExtraTemplateKeys {
+		o := *opts
+		o.TemplateKey = key
+		execConfigs, err := ctrl.getExecConfigs(cfg, &o)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = execConfigs
+	}
+	return ret, nil
+}
+
 // getExecConfig returns matched ExecConfig.
 // If no ExecConfig matches, the second returned value is false.
 func (ctrl *ExecController) getExecConfig(
 	execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams,
 ) (*config.ExecConfig, bool, error) {
 	for _, execConfig := range execConfigs {
-		f, err := ctrl.Expr.Match(execConfig.When, cmtParams)
+		f, err := ctrl.matchExecConfig(execConfig, cmtParams)
 		if err != nil {
-			return nil, false, fmt.Errorf("test a condition is matched: %w", err)
+			return nil, false, err
 		}
 		if !f {
 			continue
@@ -201,13 +505,63 @@ func (ctrl *ExecController) getExecConfig(
 	return nil, false, nil
 }
 
+func (ctrl *ExecController) matchExecConfig(execConfig *config.ExecConfig, cmtParams *ExecCommentParams) (bool, error) {
+	params, err := applyExitCodeFromOutput(execConfig, cmtParams)
+	if err != nil {
+		return false, err
+	}
+	f, err := ctrl.Expr.Match(execConfig.When, params)
+	if err != nil {
+		return false, fmt.Errorf("test a condition is matched: %w", err)
+	}
+	return f, nil
+}
+
+// getMatchedExecConfigNames returns names of all matched ExecConfigs.
+// If the name of ExecConfig is empty, the condition `when` is used as the name.
+func (ctrl *ExecController) getMatchedExecConfigNames(
+	execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams,
+) ([]string, error) {
+	names := []string{}
+	for _, execConfig := range execConfigs {
+		f, err := ctrl.matchExecConfig(execConfig, cmtParams)
+		if err != nil {
+			return nil, err
+		}
+		if !f {
+			continue
+		}
+		name := execConfig.Name
+		if name == "" {
+			name = execConfig.When
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
 // getComment returns Comment.
 // If the second returned value is false, no comment is posted.
-func (ctrl *ExecController) getComment(execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams, templates map[string]string) (*github.Comment, bool, error) { //nolint:funlen
+func (ctrl *ExecController) getComment(ctx context.Context, execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams, templates map[string]string) (*github.Comment, bool, error) { //nolint:funlen
 	tpl := cmtParams.Template
 	tplForTooLong := ""
+	tooLongStrategy := ""
+	truncateOutput := ""
+	preCommentCommand := ""
+	reviewPath := ""
+	reviewLine := ""
+	updateCondition := ""
+	var extraMetadata map[string]string
 	var embeddedVarNames []string
+	debugConfig := map[string]interface{}{
+		"Command": "exec",
+	}
 	if tpl == "" {
+		c, err := ctrl.setCommentExists(ctx, cmtParams)
+		if err != nil {
+			return nil, false, err
+		}
+		cmtParams = c
 		execConfig, f, err := ctrl.getExecConfig(execConfigs, cmtParams)
 		if err != nil {
 			return nil, false, err
@@ -218,9 +572,62 @@ func (ctrl *ExecController) getComment(execConfigs []*config.ExecConfig, cmtPara
 		if execConfig.DontComment {
 			return nil, false, nil
 		}
+		var matchedConfigs []string
+		if cmtParams.CollectMatchedConfigs {
+			names, err := ctrl.getMatchedExecConfigNames(execConfigs, cmtParams)
+			if err != nil {
+				return nil, false, err
+			}
+			logrus.WithFields(logrus.Fields{
+				"matched_configs": names,
+			}).Debug("matched exec configs")
+			matchedConfigs = names
+			debugConfig["MatchedConfigs"] = names
+		}
 		tpl = execConfig.Template
 		tplForTooLong = execConfig.TemplateForTooLong
+		tooLongStrategy = execConfig.TooLongStrategy
+		truncateOutput = execConfig.TruncateOutput
+		preCommentCommand = execConfig.PreCommentCommand
+		reviewPath = execConfig.Path
+		reviewLine = execConfig.Line
 		embeddedVarNames = execConfig.EmbeddedVarNames
+		extraMetadata = execConfig.Metadata
+		updateCondition = execConfig.UpdateCondition
+		debugConfig["When"] = execConfig.When
+		cmtParams, err = applyExitCodeFromOutput(execConfig, cmtParams)
+		if err != nil {
+			return nil, false, err
+		}
+		cmtParams = filterOutputs(execConfig, cmtParams)
+		if len(execConfig.IncludeEnv) != 0 {
+			c := *cmtParams
+			c.Command.Env = filterEnv(execConfig.IncludeEnv, ctrl.Getenv)
+			cmtParams = &c
+		}
+		if matchedConfigs != nil {
+			c := *cmtParams
+			c.MatchedConfigs = matchedConfigs
+			cmtParams = &c
+		}
+	}
+	if err := validateTooLongStrategy(tooLongStrategy); err != nil {
+		return nil, false, err
+	}
+	if err := validateTruncateOutput(truncateOutput); err != nil {
+		return nil, false, err
+	}
+	if exist, err := existsIdempotentComment(ctx, ctrl.GitHub, cmtParams.Org, cmtParams.Repo, cmtParams.PRNumber, cmtParams.IdempotencyKey, ctrl.Config.MetadataSchema); err != nil {
+		return nil, false, err
+	} else if exist {
+		return nil, false, nil
+	}
+	if cmtParams.MergeVarsFromComment {
+		c, err := ctrl.mergeVarsFromPreviousComment(ctx, cmtParams)
+		if err != nil {
+			return nil, false, err
+		}
+		cmtParams = c
 	}
 
 	body, err := ctrl.Renderer.Render(tpl, templates, cmtParams)
@@ -231,6 +638,13 @@ func (ctrl *ExecController) getComment(execConfigs []*config.ExecConfig, cmtPara
 	if err != nil {
 		return nil, false, fmt.Errorf("render a comment template_for_too_long: %w", err)
 	}
+	if preCommentCommand != "" {
+		cmtParams, body, bodyForTooLong = ctrl.applyPreCommentCommand(ctx, preCommentCommand, tpl, tplForTooLong, templates, cmtParams, body, bodyForTooLong)
+	}
+	path, line, err := ctrl.renderReviewPosition(reviewPath, reviewLine, templates, cmtParams)
+	if err != nil {
+		return nil, false, err
+	}
 
 	cmtCtrl := CommentController{
 		GitHub:   ctrl.GitHub,
@@ -239,47 +653,244 @@ func (ctrl *ExecController) getComment(execConfigs []*config.ExecConfig, cmtPara
 		Platform: ctrl.Platform,
 	}
 
-	embeddedMetadata := make(map[string]interface{}, len(embeddedVarNames))
-	for _, name := range embeddedVarNames {
-		if v, ok := cmtParams.Vars[name]; ok {
-			embeddedMetadata[name] = v
+	embeddedComment := ""
+	if !cmtParams.NoMetadata {
+		embeddedVarNames = excludeNoEmbed(embeddedVarNames, ctrl.Config.NoEmbed)
+		embeddedMetadata := make(map[string]interface{}, len(embeddedVarNames))
+		for _, name := range embeddedVarNames {
+			if v, ok := cmtParams.Vars[name]; ok {
+				embeddedMetadata[name] = v
+			}
+		}
+		if err := validateMetadataVars(embeddedMetadata, ctrl.Config.MetadataSchema); err != nil {
+			return nil, false, err
+		}
+
+		data := map[string]interface{}{
+			"SHA1":        cmtParams.SHA1,
+			"TemplateKey": cmtParams.TemplateKey,
+			"Vars":        embeddedMetadata,
+		}
+		if cmtParams.Target != "" {
+			data["Target"] = cmtParams.Target
+		}
+		if cmtParams.IdempotencyKey != "" {
+			data["IdempotencyKey"] = cmtParams.IdempotencyKey
+		}
+		extra, err := renderExtraMetadata(ctrl.Renderer, extraMetadata, templates, cmtParams)
+		if err != nil {
+			return nil, false, err
+		}
+		cmtCtrl.ExtraMetadata = extra
+		a, err := cmtCtrl.getEmbeddedComment(data)
+		if err != nil {
+			return nil, false, err
 		}
+		embeddedComment = a
 	}
 
-	embeddedComment, err := cmtCtrl.getEmbeddedComment(map[string]interface{}{
-		"SHA1":        cmtParams.SHA1,
-		"TemplateKey": cmtParams.TemplateKey,
-		"Vars":        embeddedMetadata,
-	})
+	footers, err := renderFooters(ctrl.Renderer, ctrl.Config.Footers, templates, cmtParams)
 	if err != nil {
 		return nil, false, err
 	}
 
-	body += embeddedComment
-	bodyForTooLong += embeddedComment
+	debug := ""
+	if cmtParams.DebugFooter {
+		a, err := renderDebugFooter(&debugInfo{
+			TemplateKey: cmtParams.TemplateKey,
+			Config:      debugConfig,
+			Vars:        cmtParams.Vars,
+			RunID:       getRunID(ctrl.Getenv),
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		debug = a
+	}
+
+	cmt := &github.Comment{
+		PRNumber:         cmtParams.PRNumber,
+		Org:              cmtParams.Org,
+		Repo:             cmtParams.Repo,
+		Body:             body,
+		BodyForTooLong:   bodyForTooLong,
+		Metadata:         embeddedComment,
+		Debug:            debug,
+		MinimizeOnCreate: cmtParams.MinimizeOnCreate,
+		TooLongStrategy:  tooLongStrategy,
+		SHA1:             cmtParams.SHA1,
+		Vars:             cmtParams.Vars,
+		TemplateKey:      cmtParams.TemplateKey,
+		Footers:          footers,
+		Path:             path,
+		Line:             line,
+	}
+	// the body transformed by pre_comment_command can't be re-rendered
+	if truncateOutput != "" && preCommentCommand == "" {
+		cmt.FitBody = ctrl.newFitBody(tpl, templates, cmtParams, truncateOutput)
+	}
+	if cmtParams.ValidateMentions != "" {
+		cmt.MentionBody = ctrl.newMentionBody(tpl, templates, cmtParams)
+	}
+	updateCondition = getExecUpdateCondition(updateCondition, cmtParams.UpdateCondition, cmtParams.TargetUpdateCondition, ctrl.Config.DefaultUpdateCondition)
+	if updateCondition != "" && cmt.PRNumber != 0 && cmt.Path == "" {
+		comment, err := searchUpdatedComment(ctx, ctrl.GitHub, ctrl.Expr, ctrl.Config, cmt, updateCondition, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		if comment != nil {
+			cmt.CommentID = comment.DatabaseID
+		}
+	}
+	return cmt, true, nil
+}
+
+// setCommentExists sets CommentExists so that when can refer to commentExists.
+// The update condition of exec configs isn't used because exec configs are matched by when.
+// cmtParams isn't modified because it's shared with other template keys.
+func (ctrl *ExecController) setCommentExists(ctx context.Context, cmtParams *ExecCommentParams) (*ExecCommentParams, error) {
+	condition := getExecUpdateCondition("", cmtParams.UpdateCondition, cmtParams.TargetUpdateCondition, ctrl.Config.DefaultUpdateCondition)
+	if condition == "" || cmtParams.PRNumber == 0 {
+		return cmtParams, nil
+	}
+	comment, err := searchUpdatedComment(ctx, ctrl.GitHub, ctrl.Expr, ctrl.Config, &github.Comment{
+		Org:         cmtParams.Org,
+		Repo:        cmtParams.Repo,
+		PRNumber:    cmtParams.PRNumber,
+		SHA1:        cmtParams.SHA1,
+		TemplateKey: cmtParams.TemplateKey,
+		Vars:        cmtParams.Vars,
+	}, condition, 0)
+	if err != nil {
+		return nil, err
+	}
+	c := *cmtParams
+	c.CommentExists = comment != nil
+	return &c, nil
+}
+
+// getExecUpdateCondition returns the update condition of exec.
+// The precedence is the exec config, the command line option, the target derived by auto_target,
+// and the default update condition of the configuration file.
+func getExecUpdateCondition(execConfigCondition, optCondition, targetCondition, defaultCondition string) string {
+	if execConfigCondition != "" {
+		return execConfigCondition
+	}
+	if optCondition != "" {
+		return optCondition
+	}
+	if targetCondition != "" {
+		return targetCondition
+	}
+	return defaultCondition
+}
+
+// mergeVarsFromPreviousComment merges the embedded Vars of the previous comment into Vars with the lowest precedence.
+// The previous comment is the latest comment which github-comment posted with the same template key and target.
+// cmtParams isn't modified because Vars are shared with other template keys.
+func (ctrl *ExecController) mergeVarsFromPreviousComment(ctx context.Context, cmtParams *ExecCommentParams) (*ExecCommentParams, error) {
+	if cmtParams.PRNumber == 0 {
+		return cmtParams, nil
+	}
+	login, err := ctrl.GitHub.GetAuthenticatedUser(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("get an authenticated user")
+	}
+	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+		Org:      cmtParams.Org,
+		Repo:     cmtParams.Repo,
+		PRNumber: cmtParams.PRNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list issue or pull request comments to merge embedded vars: %w", err)
+	}
+	var previous *github.IssueComment
+	for _, comment := range comments {
+		if comment.IsMinimized {
+			continue
+		}
+		if login != "" && comment.Author.Login != login {
+			continue
+		}
+		metadata := map[string]interface{}{}
+		if !extractMetaFromComment(comment.Body, &metadata, ctrl.Config.MetadataSchema) {
+			continue
+		}
+		templateKey, _ := metadata["TemplateKey"].(string)
+		target, _ := metadata["Target"].(string)
+		if templateKey != cmtParams.TemplateKey || target != cmtParams.Target {
+			continue
+		}
+		previous = comment
+	}
+	if previous == nil {
+		return cmtParams, nil
+	}
+	params := *cmtParams
+	params.Vars = make(map[string]interface{}, len(cmtParams.Vars))
+	for k, v := range cmtParams.Vars {
+		params.Vars[k] = v
+	}
+	mergeVarsFromComment(params.Vars, previous.Body, ctrl.Config.MetadataSchema)
+	return &params, nil
+}
+
+// filterOutputs returns a copy of cmtParams whose outputs excluded by execConfig are blanked.
+func filterOutputs(execConfig *config.ExecConfig, cmtParams *ExecCommentParams) *ExecCommentParams {
+	params := *cmtParams
+	if execConfig.IncludeStdout != nil && !*execConfig.IncludeStdout {
+		params.Stdout = ""
+	}
+	if execConfig.IncludeStderr != nil && !*execConfig.IncludeStderr {
+		params.Stderr = ""
+	}
+	if execConfig.IncludeCombined != nil && !*execConfig.IncludeCombined {
+		params.CombinedOutput = ""
+	}
+	params.CombinedOutput = trimOutput(params.CombinedOutput, execConfig.MaxCombinedOutputSize, execConfig.CombinedOutputKeep)
+	return &params
+}
 
-	return &github.Comment{
-		PRNumber:       cmtParams.PRNumber,
-		Org:            cmtParams.Org,
-		Repo:           cmtParams.Repo,
-		Body:           body,
-		BodyForTooLong: bodyForTooLong,
-		SHA1:           cmtParams.SHA1,
-		Vars:           cmtParams.Vars,
-		TemplateKey:    cmtParams.TemplateKey,
-	}, true, nil
+// filterEnv returns environment variables whose names are included in names.
+// Unset environment variables are excluded.
+func filterEnv(names []string, getenv func(string) string) map[string]string {
+	env := make(map[string]string, len(names))
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			env[name] = v
+		}
+	}
+	return env
+}
+
+// trimOutput trims the output to maxSize bytes.
+// If keep is "head", the beginning of the output is kept. Otherwise the end of the output is kept.
+// If maxSize is zero or less, the output isn't trimmed.
+func trimOutput(output string, maxSize int, keep string) string {
+	if maxSize <= 0 || len(output) <= maxSize {
+		return output
+	}
+	if keep == config.OutputKeepHead {
+		return output[:runeStartBefore(output, maxSize)] + fmt.Sprintf("\n... (%d bytes are omitted)", len(output)-maxSize)
+	}
+	return fmt.Sprintf("(%d bytes are omitted) ...\n", len(output)-maxSize) + output[runeStartAfter(output, len(output)-maxSize):]
 }
 
 func (ctrl *ExecController) post(
 	ctx context.Context, execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams,
 	templates map[string]string,
-) error {
-	cmt, f, err := ctrl.getComment(execConfigs, cmtParams, templates)
+) (bool, error) {
+	if matched, err := checkPRBase(ctx, ctrl.GitHub, cmtParams.Org, cmtParams.Repo, cmtParams.PRNumber, cmtParams.PRBase); err != nil {
+		return false, err
+	} else if !matched {
+		return false, nil
+	}
+	cmt, f, err := ctrl.getComment(ctx, execConfigs, cmtParams, templates)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if !f {
-		return nil
+		return false, nil
 	}
 	logrus.WithFields(logrus.Fields{
 		"org":       cmt.Org,
@@ -289,18 +900,94 @@ func (ctrl *ExecController) post(
 	}).Debug("comment meta data")
 
 	cmtCtrl := CommentController{
-		GitHub: ctrl.GitHub,
-		Expr:   ctrl.Expr,
-		Getenv: ctrl.Getenv,
+		GitHub:           ctrl.GitHub,
+		Expr:             ctrl.Expr,
+		Getenv:           ctrl.Getenv,
+		ValidateMentions: cmtParams.ValidateMentions,
+		MetadataOut:      cmtParams.MetadataOut,
+		Version:          ctrl.Version,
+		RedactPatterns:   ctrl.Config.RedactPatterns,
+		CommentLimit:     getCommentLimit(cmtParams.PRCommentLimit, ctrl.Config.MaxCommentsPerPR),
+		Cooldown:         newCooldown(cmtParams.CooldownFile, cmtParams.Cooldown, cmtParams.TemplateKey, cmtParams.Target),
+		Summary:          newSummary(cmtParams.Summary, cmtParams.TemplateKey, cmtParams.Target, cmtParams.Matrix),
+		MetadataSchema:   ctrl.Config.MetadataSchema,
 	}
-	return cmtCtrl.Post(ctx, cmt, map[string]interface{}{
+	if err := cmtCtrl.Post(ctx, cmt, map[string]interface{}{
 		"Command": map[string]interface{}{
 			"ExitCode":       cmtParams.ExitCode,
 			"JoinCommand":    cmtParams.JoinCommand,
-			"Command":        cmtParams.Command,
+			"Command":        cmtParams.Command.Cmd,
 			"Stdout":         cmtParams.Stdout,
 			"Stderr":         cmtParams.Stderr,
 			"CombinedOutput": cmtParams.CombinedOutput,
+			"Attempts":       cmtParams.Command.Attempts,
+			"Env":            cmtParams.Command.Env,
 		},
-	})
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TimeoutExitCode is the exit code when the command times out. It's same as the command timeout of GNU coreutils
+const TimeoutExitCode = 124
+
+// runWithTimeout runs the command. If opts.Timeout is positive, the whole run including retries is bounded by it.
+// If the command times out, the exit code is TimeoutExitCode and the output captured until then is returned.
+func (ctrl *ExecController) runWithTimeout(ctx context.Context, opts *option.ExecOptions) (*execute.Result, int, error) {
+	if opts.Timeout <= 0 {
+		return ctrl.run(ctx, opts)
+	}
+	runCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	result, attempts, err := ctrl.run(runCtx, opts)
+	if !errors.Is(runCtx.Err(), context.DeadlineExceeded) || ctx.Err() != nil {
+		return result, attempts, err
+	}
+	logrus.WithFields(logrus.Fields{
+		"timeout":  opts.Timeout,
+		"attempts": attempts,
+	}).Warn("the command timed out")
+	result.ExitCode = TimeoutExitCode
+	if err == nil {
+		err = errors.New("the command timed out")
+	} else {
+		err = fmt.Errorf("the command timed out: %w", err)
+	}
+	return result, attempts, err
+}
+
+// run runs the command.
+// If the command fails, the command is retried up to opts.Retry times with opts.RetryDelay.
+// The command isn't retried once ctx is done, e.g. after the timeout.
+// The result of the last run and the number of attempts are returned.
+// Note that the standard input is consumed by the first run.
+func (ctrl *ExecController) run(ctx context.Context, opts *option.ExecOptions) (*execute.Result, int, error) {
+	attempts := 0
+	for {
+		attempts++
+		result, err := ctrl.Executor.Run(ctx, &execute.Params{
+			Cmd:   opts.Args[0],
+			Args:  opts.Args[1:],
+			Stdin: ctrl.Stdin,
+		})
+		if err == nil || attempts > opts.Retry || result.Signal != nil || ctx.Err() != nil {
+			return result, attempts, err
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"attempts":    attempts,
+			"exit_code":   result.ExitCode,
+			"retry_delay": opts.RetryDelay,
+		}).Warn("retry the command")
+		if opts.RetryDelay <= 0 {
+			continue
+		}
+		timer := time.NewTimer(opts.RetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, attempts, err
+		case <-timer.C:
+		}
+	}
 }
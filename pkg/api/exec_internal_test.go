@@ -1,11 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/execute"
 	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
 )
 
 func TestExecController_getExecConfig(t *testing.T) { //nolint:funlen
@@ -76,3 +86,812 @@ func TestExecController_getExecConfig(t *testing.T) { //nolint:funlen
 		})
 	}
 }
+
+func Test_trimOutput(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		output  string
+		maxSize int
+		keep    string
+		exp     string
+	}{
+		{
+			title:  "max size isn't set",
+			output: "hello",
+			exp:    "hello",
+		},
+		{
+			title:   "output is short",
+			output:  "hello",
+			maxSize: 10,
+			exp:     "hello",
+		},
+		{
+			title:   "keep the tail",
+			output:  "hello world",
+			maxSize: 5,
+			exp:     "(6 bytes are omitted) ...\nworld",
+		},
+		{
+			title:   "keep the head",
+			output:  "hello world",
+			maxSize: 5,
+			keep:    "head",
+			exp:     "hello\n... (6 bytes are omitted)",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, trimOutput(d.output, d.maxSize, d.keep))
+		})
+	}
+}
+
+func TestExecController_getExtraExecConfigs(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Exec: map[string][]*config.ExecConfig{
+			"summary": {
+				{
+					When: "true",
+				},
+			},
+			"detail": {
+				{
+					When: "ExitCode != 0",
+				},
+			},
+		},
+	}
+	data := []struct {
+		title string
+		opts  *option.ExecOptions
+		exp   [][]*config.ExecConfig
+		isErr bool
+	}{
+		{
+			title: "all template keys are found",
+			opts: &option.ExecOptions{
+				Options: option.Options{
+					TemplateKey: "summary",
+				},
+				ExtraTemplateKeys: []string{"detail"},
+			},
+			exp: [][]*config.ExecConfig{cfg.Exec["detail"]},
+		},
+		{
+			title: "an extra template key isn't found",
+			opts: &option.ExecOptions{
+				Options: option.Options{
+					TemplateKey: "summary",
+				},
+				ExtraTemplateKeys: []string{"foo"},
+			},
+			isErr: true,
+		},
+		{
+			title: "the first template key isn't found",
+			opts: &option.ExecOptions{
+				Options: option.Options{
+					TemplateKey: "foo",
+				},
+				ExtraTemplateKeys: []string{"detail"},
+			},
+			isErr: true,
+		},
+	}
+	ctrl := &ExecController{}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			execConfigs, err := ctrl.getExtraExecConfigs(cfg, d.opts)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, execConfigs)
+		})
+	}
+}
+
+type blockingExecutor struct{}
+
+func (executor *blockingExecutor) Run(ctx context.Context, params *execute.Params) (*execute.Result, error) {
+	<-ctx.Done()
+	return &execute.Result{
+		ExitCode:       -1,
+		CombinedOutput: "partial output",
+	}, ctx.Err()
+}
+
+func TestExecController_runWithTimeout(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		retry int
+	}{
+		{
+			title: "timeout",
+		},
+		{
+			title: "the command isn't retried after the timeout",
+			retry: 3,
+		},
+	}
+	ctrl := &ExecController{
+		Executor: &blockingExecutor{},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			result, attempts, err := ctrl.runWithTimeout(context.Background(), &option.ExecOptions{
+				Args:    []string{"sleep", "10"},
+				Timeout: 10 * time.Millisecond,
+				Retry:   d.retry,
+			})
+			require.NotNil(t, err)
+			require.Equal(t, 1, attempts)
+			require.Equal(t, TimeoutExitCode, result.ExitCode)
+			require.Equal(t, "partial output", result.CombinedOutput)
+		})
+	}
+}
+
+func TestExecController_checkExecConfigMatched(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title            string
+		execConfigs      []*config.ExecConfig
+		extraExecConfigs [][]*config.ExecConfig
+		extraKeys        []string
+		isErr            bool
+	}{
+		{
+			title: "matched",
+			execConfigs: []*config.ExecConfig{
+				{
+					When: "false",
+				},
+				{
+					When: "true",
+				},
+			},
+		},
+		{
+			title: "no exec config matches",
+			execConfigs: []*config.ExecConfig{
+				{
+					When: "false",
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "no exec config of an extra template key matches",
+			execConfigs: []*config.ExecConfig{
+				{
+					When: "true",
+				},
+			},
+			extraExecConfigs: [][]*config.ExecConfig{
+				{
+					{
+						When: "false",
+					},
+				},
+			},
+			extraKeys: []string{"detail"},
+			isErr:     true,
+		},
+	}
+	ctrl := &ExecController{
+		Expr: &expr.Expr{},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := ctrl.checkExecConfigMatched(d.execConfigs, &ExecCommentParams{}, d.extraExecConfigs, d.extraKeys)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+type countExecutor struct {
+	exitCode int
+	runs     int
+}
+
+func (executor *countExecutor) Run(ctx context.Context, params *execute.Params) (*execute.Result, error) {
+	executor.runs++
+	result := &execute.Result{
+		ExitCode: executor.exitCode,
+	}
+	if executor.exitCode != 0 {
+		return result, errors.New("the command failed")
+	}
+	return result, nil
+}
+
+func TestExecController_Exec_requirePR(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		exitCode int
+		expCode  int
+	}{
+		{
+			title:   "the command succeeds",
+			expCode: ExitCodeNoPR,
+		},
+		{
+			title:    "the exit code of the command takes precedence",
+			exitCode: 2,
+			expCode:  2,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			executor := &countExecutor{exitCode: d.exitCode}
+			gh := &fakeGitHub{}
+			ctrl := &ExecController{
+				Executor: executor,
+				GitHub:   gh,
+				Config:   &config.Config{},
+				Getenv: func(string) string {
+					return ""
+				},
+			}
+			err := ctrl.Exec(context.Background(), &option.ExecOptions{
+				Options: option.Options{
+					Org:       "suzuki-shunsuke",
+					Repo:      "github-comment",
+					RequirePR: true,
+				},
+				Args: []string{"true"},
+			})
+			require.NotNil(t, err)
+			require.Equal(t, d.expCode, ecerror.GetExitCode(err))
+			require.Equal(t, 1, executor.runs)
+			require.Nil(t, gh.createdComment)
+		})
+	}
+}
+
+func TestExecController_Exec_validateBeforeRun(t *testing.T) {
+	t.Parallel()
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.Nil(t, os.WriteFile(stateFile, []byte("{"), 0o600))
+	data := []struct {
+		title string
+		opts  *option.ExecOptions
+	}{
+		{
+			title: "invalid state file",
+			opts: &option.ExecOptions{
+				StateFile: stateFile,
+			},
+		},
+		{
+			title: "undefined variable",
+			opts: &option.ExecOptions{
+				Options: option.Options{
+					Vars: map[string]string{"name": "${foo}"},
+				},
+			},
+		},
+		{
+			title: "invalid matrix",
+			opts: &option.ExecOptions{
+				Options: option.Options{
+					MatrixJSON: "{",
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			executor := &countExecutor{exitCode: 2}
+			ctrl := &ExecController{
+				Executor: executor,
+				GitHub:   &fakeGitHub{},
+				Config:   &config.Config{},
+				Getenv: func(string) string {
+					return ""
+				},
+			}
+			d.opts.Org = "suzuki-shunsuke"
+			d.opts.Repo = "github-comment"
+			d.opts.PRNumber = 1
+			d.opts.Args = []string{"true"}
+			require.NotNil(t, ctrl.Exec(context.Background(), d.opts))
+			// the command isn't run, so the exit code of the command isn't lost
+			require.Equal(t, 0, executor.runs)
+		})
+	}
+}
+
+func TestExecController_Exec_invalidSessionFile(t *testing.T) {
+	t.Parallel()
+	sessionFile := filepath.Join(t.TempDir(), "session.json")
+	require.Nil(t, os.WriteFile(sessionFile, []byte("{"), 0o600))
+	executor := &countExecutor{exitCode: 2}
+	ctrl := &ExecController{
+		Executor: executor,
+		GitHub:   &fakeGitHub{},
+		Expr:     &expr.Expr{},
+		Renderer: &template.Renderer{},
+		Config:   &config.Config{},
+		Getenv: func(string) string {
+			return ""
+		},
+	}
+	err := ctrl.Exec(context.Background(), &option.ExecOptions{
+		Options: option.Options{
+			Org:         "suzuki-shunsuke",
+			Repo:        "github-comment",
+			PRNumber:    1,
+			Token:       "xxx",
+			TemplateKey: "default",
+		},
+		Args:             []string{"true"},
+		FirstFailureOnly: true,
+		SessionFile:      sessionFile,
+	})
+	require.NotNil(t, err)
+	require.Equal(t, 2, ecerror.GetExitCode(err))
+	require.Equal(t, 1, executor.runs)
+}
+
+func TestExecController_getExecConfigs_defaultTemplateWithDelims(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		delims   string
+		renderer *template.Renderer
+	}{
+		{
+			title:    "default delimiters",
+			renderer: &template.Renderer{},
+		},
+		{
+			title:    "custom delimiters",
+			delims:   "<< >>",
+			renderer: &template.Renderer{LeftDelim: "<<", RightDelim: ">>"},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{}
+			execConfigs, err := ctrl.getExecConfigs(&config.Config{Delims: d.delims}, &option.ExecOptions{
+				Options: option.Options{
+					TemplateKey: "default",
+				},
+			})
+			require.Nil(t, err)
+			require.Len(t, execConfigs, 1)
+			templates := template.GetTemplates(&template.ParamGetTemplates{
+				JoinCommand:    "false",
+				CombinedOutput: "error",
+			})
+			body, err := d.renderer.Render(execConfigs[0].Template, templates, &ExecCommentParams{
+				ExitCode:    1,
+				JoinCommand: "false",
+			})
+			require.Nil(t, err)
+			require.NotContains(t, body, "template")
+			require.Contains(t, body, "false")
+		})
+	}
+}
+
+func TestExecController_matchExecConfig_comment(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			Body: "plan\n<!-- github-comment: {\"Target\":\"plan\",\"Vars\":{\"result\":\"success\"}} -->",
+		},
+	}
+	data := []struct {
+		title string
+		when  string
+		exp   bool
+	}{
+		{
+			title: "the metadata of the comment with the target",
+			when:  `comment("plan").Vars.result == "success"`,
+			exp:   true,
+		},
+		{
+			title: "no comment with the target",
+			when:  `comment("apply") == nil`,
+			exp:   true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{comments: comments}
+			ctrl := &ExecController{
+				Expr: &expr.Expr{},
+			}
+			f, err := ctrl.matchExecConfig(&config.ExecConfig{
+				When: d.when,
+			}, &ExecCommentParams{
+				CommentLookup: newLazyCommentLookup(context.Background(), gh, &github.PullRequest{
+					Org:      "suzuki-shunsuke",
+					Repo:     "github-comment",
+					PRNumber: 1,
+				}, nil),
+			})
+			require.Nil(t, err)
+			require.Equal(t, d.exp, f)
+			require.Equal(t, 1, gh.listCalls)
+		})
+	}
+}
+
+func TestExecController_mergeVarsFromPreviousComment(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		newFakeComment("bot", "<!-- github-comment: {\"TemplateKey\":\"test\",\"Vars\":{\"count\":\"1\",\"name\":\"old\"}} -->", false),
+		newFakeComment("bot", "<!-- github-comment: {\"TemplateKey\":\"test\",\"Vars\":{\"count\":\"2\",\"name\":\"old\"}} -->", false),
+		newFakeComment("bot", "<!-- github-comment: {\"TemplateKey\":\"test\",\"Vars\":{\"count\":\"3\"}} -->", true),
+		newFakeComment("octocat", "<!-- github-comment: {\"TemplateKey\":\"test\",\"Vars\":{\"count\":\"4\"}} -->", false),
+		newFakeComment("bot", "<!-- github-comment: {\"TemplateKey\":\"test\",\"Target\":\"foo\",\"Vars\":{\"count\":\"5\"}} -->", false),
+		newFakeComment("bot", "<!-- github-comment: {\"TemplateKey\":\"lint\",\"Vars\":{\"count\":\"6\"}} -->", false),
+	}
+	data := []struct {
+		title  string
+		params *ExecCommentParams
+		exp    map[string]interface{}
+	}{
+		{
+			title: "the latest comment with the same template key is merged and values are coerced by the schema",
+			params: &ExecCommentParams{
+				PRNumber:    1,
+				TemplateKey: "test",
+				Vars:        map[string]interface{}{"name": "new"},
+			},
+			exp: map[string]interface{}{"count": 2.0, "name": "new"},
+		},
+		{
+			title: "the target must match",
+			params: &ExecCommentParams{
+				PRNumber:    1,
+				TemplateKey: "test",
+				Target:      "foo",
+				Vars:        map[string]interface{}{},
+			},
+			exp: map[string]interface{}{"count": 5.0},
+		},
+		{
+			title: "no previous comment",
+			params: &ExecCommentParams{
+				PRNumber:    1,
+				TemplateKey: "build",
+				Vars:        map[string]interface{}{},
+			},
+			exp: map[string]interface{}{},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{
+				GitHub: &fakeGitHub{login: "bot", comments: comments},
+				Config: &config.Config{
+					MetadataSchema: map[string]string{
+						"count": config.MetadataTypeNumber,
+					},
+				},
+			}
+			vars := make(map[string]interface{}, len(d.params.Vars))
+			for k, v := range d.params.Vars {
+				vars[k] = v
+			}
+			params, err := ctrl.mergeVarsFromPreviousComment(context.Background(), d.params)
+			require.Nil(t, err)
+			require.Equal(t, d.exp, params.Vars)
+			// the original vars aren't modified
+			require.Equal(t, vars, d.params.Vars)
+		})
+	}
+}
+
+func TestExecController_getComment_updateCondition(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"config\"} -->",
+		},
+		{
+			DatabaseID: 2,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"cli\"} -->",
+		},
+		{
+			DatabaseID: 3,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"default\"} -->",
+		},
+		{
+			DatabaseID: 4,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"test\",\"Target\":\"test/os=ubuntu-latest\"} -->",
+		},
+	}
+	data := []struct {
+		title            string
+		configCondition  string
+		optCondition     string
+		target           string
+		defaultCondition string
+		exp              int64
+	}{
+		{
+			title: "no update condition",
+		},
+		{
+			title:            "the exec config takes precedence",
+			configCondition:  `Comment.Meta.TemplateKey == "config"`,
+			optCondition:     `Comment.Meta.TemplateKey == "cli"`,
+			defaultCondition: `Comment.Meta.TemplateKey == "default"`,
+			exp:              1,
+		},
+		{
+			title:            "the command line option takes precedence over the default",
+			optCondition:     `Comment.Meta.TemplateKey == "cli"`,
+			defaultCondition: `Comment.Meta.TemplateKey == "default"`,
+			exp:              2,
+		},
+		{
+			title:            "the default update condition",
+			defaultCondition: `Comment.Meta.TemplateKey == "default"`,
+			exp:              3,
+		},
+		{
+			title:            "the derived target takes precedence over the default",
+			target:           "test/os=ubuntu-latest",
+			defaultCondition: `Comment.Meta.TemplateKey == "default"`,
+			exp:              4,
+		},
+		{
+			title:        "the command line option takes precedence over the derived target",
+			optCondition: `Comment.Meta.TemplateKey == "cli"`,
+			target:       "test/os=ubuntu-latest",
+			exp:          2,
+		},
+		{
+			title:           "no comment matches",
+			configCondition: `Comment.Meta.TemplateKey == "foo"`,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			targetCondition := ""
+			if d.target != "" {
+				targetCondition = targetUpdateCondition(d.target)
+			}
+			ctrl := &ExecController{
+				GitHub:   &fakeGitHub{comments: comments},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config: &config.Config{
+					DefaultUpdateCondition: d.defaultCondition,
+				},
+			}
+			cmt, f, err := ctrl.getComment(context.Background(), []*config.ExecConfig{
+				{
+					When:            "true",
+					Template:        "hello",
+					UpdateCondition: d.configCondition,
+				},
+			}, &ExecCommentParams{
+				Org:                   "suzuki-shunsuke",
+				Repo:                  "github-comment",
+				PRNumber:              1,
+				TemplateKey:           "test",
+				Target:                d.target,
+				UpdateCondition:       d.optCondition,
+				TargetUpdateCondition: targetCondition,
+				Vars:                  map[string]interface{}{},
+			}, nil)
+			require.Nil(t, err)
+			require.True(t, f)
+			require.Equal(t, d.exp, cmt.CommentID)
+		})
+	}
+}
+
+func TestExecCommand(t *testing.T) {
+	t.Parallel()
+	params := &ExecCommentParams{
+		Command: ExecCommand{
+			Cmd:      "go test ./...",
+			Attempts: 3,
+		},
+	}
+	body, err := (&template.Renderer{}).Render("$ {{.Command}} ({{.Command.Attempts}} attempts)", nil, params)
+	require.Nil(t, err)
+	require.Equal(t, "$ go test ./... (3 attempts)", body)
+	ctrl := &ExecController{
+		Expr: &expr.Expr{},
+	}
+	f, err := ctrl.matchExecConfig(&config.ExecConfig{
+		When: "Command.Attempts > 1",
+	}, params)
+	require.Nil(t, err)
+	require.True(t, f)
+}
+
+func TestExecCommand_env(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		lang  string
+		env   map[string]string
+		exp   string
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "en",
+			env:   map[string]string{"GOOS": "linux"},
+			exp:   "<details><summary>Environment variables</summary>\n\n```\nGOOS=linux\n```\n\n</details>",
+		},
+		{
+			title: "ja",
+			lang:  "ja",
+			env:   map[string]string{"GOOS": "linux"},
+			exp:   "<details><summary>環境変数</summary>\n\n```\nGOOS=linux\n```\n\n</details>",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			templates := template.GetTemplates(&template.ParamGetTemplates{
+				Lang: d.lang,
+			})
+			body, err := (&template.Renderer{}).Render(`{{template "hidden_command_env" .}}`, templates, &ExecCommentParams{
+				Command: ExecCommand{
+					Env: d.env,
+				},
+			})
+			require.Nil(t, err)
+			require.Equal(t, d.exp, body)
+		})
+	}
+}
+
+func TestExecController_getComment_commentExists(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"test\"} -->",
+		},
+	}
+	data := []struct {
+		title        string
+		optCondition string
+		exp          string
+	}{
+		{
+			title:        "a comment exists",
+			optCondition: `Comment.Meta.TemplateKey == "test"`,
+			exp:          "update",
+		},
+		{
+			title:        "no comment matches",
+			optCondition: `Comment.Meta.TemplateKey == "foo"`,
+			exp:          "create",
+		},
+		{
+			title: "no update condition",
+			exp:   "create",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{
+				GitHub:   &fakeGitHub{comments: comments},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			cmt, f, err := ctrl.getComment(context.Background(), []*config.ExecConfig{
+				{
+					When:     "commentExists",
+					Template: "update",
+				},
+				{
+					When:     "!commentExists",
+					Template: "create",
+				},
+			}, &ExecCommentParams{
+				Org:             "suzuki-shunsuke",
+				Repo:            "github-comment",
+				PRNumber:        1,
+				TemplateKey:     "test",
+				UpdateCondition: d.optCondition,
+				Vars:            map[string]interface{}{},
+			}, nil)
+			require.Nil(t, err)
+			require.True(t, f)
+			require.Equal(t, d.exp, cmt.Body)
+		})
+	}
+}
+
+func TestExecController_getComment_idempotencyKey(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		key   string
+		exp   bool
+	}{
+		{
+			title: "skip posting a comment because a comment with the same key exists",
+			key:   "foo",
+		},
+		{
+			title: "no comment with the same key",
+			key:   "bar",
+			exp:   true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{
+				GitHub: &fakeGitHub{
+					comments: []*github.IssueComment{
+						newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"foo\"} -->", false),
+					},
+				},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			_, f, err := ctrl.getComment(context.Background(), []*config.ExecConfig{
+				{
+					When:     "true",
+					Template: "hello",
+				},
+			}, &ExecCommentParams{
+				Org:            "suzuki-shunsuke",
+				Repo:           "github-comment",
+				PRNumber:       1,
+				TemplateKey:    "test",
+				IdempotencyKey: d.key,
+				Vars:           map[string]interface{}{},
+			}, nil)
+			require.Nil(t, err)
+			require.Equal(t, d.exp, f)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+// applyExitCodeFromOutput overrides the exit code according to the combined output.
+//
+// 1. If exit_code_from_output is set and the output matches, the first capture group is parsed as the exit code.
+// If the output matches multiple times, the last match takes precedence.
+// 2. If fail_if_output_matches is set and the output matches, the exit code is forced to 1 if it's 0.
+//
+// If the output doesn't match, the process exit code is kept.
+// The returned ExecCommentParams is a copy, so the given one isn't changed.
+func applyExitCodeFromOutput(execConfig *config.ExecConfig, cmtParams *ExecCommentParams) (*ExecCommentParams, error) {
+	if execConfig.ExitCodeFromOutput == "" && execConfig.FailIfOutputMatches == "" {
+		return cmtParams, nil
+	}
+	params := *cmtParams
+	if execConfig.ExitCodeFromOutput != "" {
+		exitCode, f, err := extractExitCode(execConfig.ExitCodeFromOutput, cmtParams.CombinedOutput)
+		if err != nil {
+			return nil, err
+		}
+		if f {
+			params.ExitCode = exitCode
+		}
+	}
+	if execConfig.FailIfOutputMatches != "" && params.ExitCode == 0 {
+		r, err := compileOutputPattern(execConfig.FailIfOutputMatches)
+		if err != nil {
+			return nil, fmt.Errorf("compile fail_if_output_matches: %w", err)
+		}
+		if r.MatchString(cmtParams.CombinedOutput) {
+			params.ExitCode = 1
+		}
+	}
+	return &params, nil
+}
+
+// outputPatterns caches compiled exit_code_from_output and fail_if_output_matches by pattern,
+// because they're evaluated for every exec config and template key.
+var outputPatterns sync.Map //nolint:gochecknoglobals
+
+// compileOutputPattern compiles the regular expression once per pattern.
+func compileOutputPattern(pattern string) (*regexp.Regexp, error) {
+	if r, ok := outputPatterns.Load(pattern); ok {
+		return r.(*regexp.Regexp), nil //nolint:forcetypeassert
+	}
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	outputPatterns.Store(pattern, r)
+	return r, nil
+}
+
+// extractExitCode extracts the exit code from the output.
+// If the output doesn't match, the second returned value is false.
+func extractExitCode(pattern, output string) (int, bool, error) {
+	r, err := compileOutputPattern(pattern)
+	if err != nil {
+		return 0, false, fmt.Errorf("compile exit_code_from_output: %w", err)
+	}
+	if r.NumSubexp() < 1 {
+		return 0, false, fmt.Errorf("exit_code_from_output must have a capture group: %s", pattern)
+	}
+	matches := r.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0, false, nil
+	}
+	exitCode, err := strconv.Atoi(matches[len(matches)-1][1])
+	if err != nil {
+		return 0, false, fmt.Errorf("parse the exit code extracted from the output as an integer: %w", err)
+	}
+	return exitCode, true, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+func Test_applyExitCodeFromOutput(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	data := []struct {
+		title      string
+		execConfig *config.ExecConfig
+		output     string
+		exp        int
+		isErr      bool
+	}{
+		{
+			title:      "exit_code_from_output isn't set",
+			execConfig: &config.ExecConfig{},
+			output:     "EXIT_CODE=3",
+			exp:        1,
+		},
+		{
+			title: "output doesn't match",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput: `EXIT_CODE=(\d+)`,
+			},
+			output: "hello",
+			exp:    1,
+		},
+		{
+			title: "output matches",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput: `EXIT_CODE=(\d+)`,
+			},
+			output: "hello\nEXIT_CODE=3\n",
+			exp:    3,
+		},
+		{
+			title: "the last match takes precedence",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput: `EXIT_CODE=(\d+)`,
+			},
+			output: "EXIT_CODE=3\nEXIT_CODE=0\n",
+			exp:    0,
+		},
+		{
+			title: "no capture group",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput: `EXIT_CODE=\d+`,
+			},
+			output: "EXIT_CODE=3",
+			isErr:  true,
+		},
+		{
+			title: "not integer",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput: `EXIT_CODE=(\w+)`,
+			},
+			output: "EXIT_CODE=foo",
+			isErr:  true,
+		},
+		{
+			title: "fail_if_output_matches",
+			execConfig: &config.ExecConfig{
+				ExitCodeFromOutput:  `EXIT_CODE=(\d+)`,
+				FailIfOutputMatches: `--- FAIL`,
+			},
+			output: "--- FAIL: TestFoo\nEXIT_CODE=0",
+			exp:    1,
+		},
+		{
+			title: "fail_if_output_matches doesn't override non zero exit code",
+			execConfig: &config.ExecConfig{
+				FailIfOutputMatches: `--- FAIL`,
+			},
+			output: "--- FAIL: TestFoo",
+			exp:    1,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			cmtParams := &ExecCommentParams{
+				ExitCode:       1,
+				CombinedOutput: d.output,
+			}
+			params, err := applyExitCodeFromOutput(d.execConfig, cmtParams)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, params.ExitCode)
+			require.Equal(t, 1, cmtParams.ExitCode)
+		})
+	}
+}
+
+func Test_compileOutputPattern(t *testing.T) {
+	t.Parallel()
+	r1, err := compileOutputPattern(`EXIT_CODE=(\d+)`)
+	require.Nil(t, err)
+	r2, err := compileOutputPattern(`EXIT_CODE=(\d+)`)
+	require.Nil(t, err)
+	// the compiled regular expression is reused
+	require.True(t, r1 == r2)
+	_, err = compileOutputPattern(`(`)
+	require.NotNil(t, err)
+}
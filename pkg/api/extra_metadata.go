@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+)
+
+// renderExtraMetadata renders values of the metadata in the configuration file.
+func renderExtraMetadata(renderer Renderer, metadata map[string]string, templates map[string]string, params interface{}) (map[string]interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	ret := make(map[string]interface{}, len(metadata))
+	for k, tpl := range metadata {
+		v, err := renderer.Render(tpl, templates, params)
+		if err != nil {
+			return nil, fmt.Errorf("render the metadata %s: %w", k, err)
+		}
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+// mergeExtraMetadata merges extra into the embedded metadata data.
+// Built-in keys such as SHA1 and Vars can't be overwritten because update and hide conditions depend on them.
+func mergeExtraMetadata(data, extra map[string]interface{}) error {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	// sort keys to make the error message stable
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := data[k]; ok {
+			return fmt.Errorf("the metadata %s conflicts with the built-in metadata", k)
+		}
+		data[k] = extra[k]
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeExtraMetadata(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		data  map[string]interface{}
+		extra map[string]interface{}
+		exp   map[string]interface{}
+		isErr bool
+	}{
+		{
+			title: "no extra metadata",
+			data:  map[string]interface{}{"SHA1": "xxx"},
+			exp:   map[string]interface{}{"SHA1": "xxx"},
+		},
+		{
+			title: "merge",
+			data:  map[string]interface{}{"SHA1": "xxx"},
+			extra: map[string]interface{}{"Attempt": "2"},
+			exp:   map[string]interface{}{"SHA1": "xxx", "Attempt": "2"},
+		},
+		{
+			title: "conflict",
+			data:  map[string]interface{}{"SHA1": "xxx"},
+			extra: map[string]interface{}{"SHA1": "yyy"},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := mergeExtraMetadata(d.data, d.extra)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, d.data)
+		})
+	}
+}
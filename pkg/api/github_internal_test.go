@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// fakeGitHub is a GitHub client for tests.
+// Methods which aren't overridden panic because the embedded interface is nil.
+type fakeGitHub struct {
+	GitHub
+	login          string
+	comments       []*github.IssueComment
+	listCalls      int
+	createdComment *github.Comment
+	// createdComments are all created or updated comments
+	createdComments []*github.Comment
+	// reactions are "<comment id>:<content>" of added reactions
+	reactions   []string
+	prInfo      *github.PRInfo
+	prInfoCalls int
+	// deletedComments are database ids of deleted comments
+	deletedComments []int64
+	// users are logins of existing users
+	users []string
+}
+
+func (gh *fakeGitHub) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	return gh.login, nil
+}
+
+func (gh *fakeGitHub) UserExists(ctx context.Context, login string) (bool, error) {
+	for _, user := range gh.users {
+		if user == login {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (gh *fakeGitHub) ListComments(ctx context.Context, pr *github.PullRequest) ([]*github.IssueComment, error) {
+	gh.listCalls++
+	return gh.comments, nil
+}
+
+func (gh *fakeGitHub) CreateComment(ctx context.Context, cmt *github.Comment) error {
+	gh.createdComment = cmt
+	gh.createdComments = append(gh.createdComments, cmt)
+	return nil
+}
+
+func (gh *fakeGitHub) AddReaction(ctx context.Context, org, repo string, commentID int64, content string) error {
+	gh.reactions = append(gh.reactions, strconv.FormatInt(commentID, 10)+":"+content)
+	return nil
+}
+
+func (gh *fakeGitHub) DeleteComment(ctx context.Context, org, repo string, commentID int64) error {
+	gh.deletedComments = append(gh.deletedComments, commentID)
+	return nil
+}
+
+func (gh *fakeGitHub) PRInfo(ctx context.Context, owner, repo string, number int) (*github.PRInfo, error) {
+	gh.prInfoCalls++
+	return gh.prInfo, nil
+}
+
+func newFakeComment(login, body string, minimized bool) *github.IssueComment {
+	comment := &github.IssueComment{
+		Body:        body,
+		IsMinimized: minimized,
+	}
+	comment.Author.Login = login
+	return comment
+}
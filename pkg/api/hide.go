@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
 	"github.com/suzuki-shunsuke/github-comment/pkg/github"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
 )
 
 type HideController struct {
@@ -25,6 +27,7 @@ type HideController struct {
 	Platform Platform
 	Config   *config.Config
 	Expr     Expr
+	Renderer Renderer
 }
 
 func (ctrl *HideController) Hide(ctx context.Context, opts *option.HideOptions) error {
@@ -35,19 +38,78 @@ func (ctrl *HideController) Hide(ctx context.Context, opts *option.HideOptions)
 	if err != nil {
 		return err
 	}
-	nodeIDs, err := listHiddenComments(
+	comments, err := listHiddenComments(
 		ctx, ctrl.GitHub, ctrl.Expr, param, nil)
 	if err != nil {
 		return err
 	}
+	nodeIDs := make([]string, len(comments))
+	for i, comment := range comments {
+		nodeIDs[i] = comment.ID
+	}
 	logE.WithFields(logrus.Fields{
 		"count":    len(nodeIDs),
 		"node_ids": nodeIDs,
 	}).Debug("comments which would be hidden")
+	if opts.ReasonTemplate != "" {
+		ctrl.prependReason(ctx, param, comments, opts.ReasonTemplate)
+	}
 	hideComments(ctx, ctrl.GitHub, nodeIDs)
 	return nil
 }
 
+// HideReasonParams is the parameter of the template of the reason why comments are hidden.
+type HideReasonParams struct {
+	PRNumber  int
+	Org       string
+	Repo      string
+	SHA1      string
+	HideKey   string
+	Condition string
+	Vars      map[string]interface{}
+}
+
+// prependReason edits comments to prepend the reason why they are hidden before they are hidden.
+// GitHub doesn't allow annotating the reason when a comment is minimized,
+// so the reason is left in the comment body as an audit trail.
+// If it fails to edit a comment, the comment is hidden anyway.
+func (ctrl *HideController) prependReason(ctx context.Context, param *ParamListHiddenComments, comments []*github.IssueComment, reasonTemplate string) {
+	ci := ""
+	if ctrl.Platform != nil {
+		ci = ctrl.Platform.CI()
+	}
+	reason, err := ctrl.Renderer.Render(reasonTemplate, template.GetTemplates(&template.ParamGetTemplates{
+		Templates: ctrl.Config.Templates,
+		CI:        ci,
+		Lang:      ctrl.Config.Lang,
+	}), &HideReasonParams{
+		PRNumber:  param.PRNumber,
+		Org:       param.Org,
+		Repo:      param.Repo,
+		SHA1:      param.SHA1,
+		HideKey:   param.HideKey,
+		Condition: param.Condition,
+		Vars:      param.Vars,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("render the reason why comments are hidden")
+		return
+	}
+	for _, comment := range comments {
+		if err := ctrl.GitHub.CreateComment(ctx, &github.Comment{
+			PRNumber:  param.PRNumber,
+			Org:       param.Org,
+			Repo:      param.Repo,
+			CommentID: comment.DatabaseID,
+			Body:      "> folded: " + reason + "\n\n" + comment.Body,
+		}); err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"node_id": comment.ID,
+			}).Error("prepend the reason why the comment is hidden")
+		}
+	}
+}
+
 func (ctrl *HideController) getParamListHiddenComments(ctx context.Context, opts *option.HideOptions) (*ParamListHiddenComments, error) { //nolint:cyclop,funlen
 	param := &ParamListHiddenComments{}
 	if ctrl.Platform != nil {
@@ -109,6 +171,9 @@ func (ctrl *HideController) getParamListHiddenComments(ctx context.Context, opts
 		Condition: hideCondition,
 		HideKey:   opts.HideKey,
 		Vars:      cfg.Vars,
+
+		AuthorAssociations: cfg.AuthorAssociations,
+		MetadataSchema:     cfg.MetadataSchema,
 	}, nil
 }
 
@@ -118,12 +183,19 @@ func hideComments(ctx context.Context, commenter GitHub, nodeIDs []string) {
 	})
 	commentHidden := false
 	for _, nodeID := range nodeIDs {
-		if err := commenter.HideComment(ctx, nodeID); err != nil {
+		minimized, err := commenter.HideComment(ctx, nodeID)
+		if err != nil {
 			logE.WithError(err).WithFields(logrus.Fields{
 				"node_id": nodeID,
 			}).Error("hide an old comment")
 			continue
 		}
+		if !minimized {
+			logE.WithFields(logrus.Fields{
+				"node_id": nodeID,
+			}).Warn("GitHub didn't minimize an old comment. The token may not have the permission to minimize it")
+			continue
+		}
 		commentHidden = true
 		logE.WithFields(logrus.Fields{
 			"node_id": nodeID,
@@ -142,6 +214,10 @@ type ParamListHiddenComments struct {
 	SHA1      string
 	PRNumber  int
 	Vars      map[string]interface{}
+	// AuthorAssociations narrows comments to ones whose author associations are included
+	AuthorAssociations []string
+	// MetadataSchema declares types of embedded variables
+	MetadataSchema map[string]string
 }
 
 func listHiddenComments( //nolint:funlen
@@ -149,7 +225,7 @@ func listHiddenComments( //nolint:funlen
 	gh GitHub, exp Expr,
 	param *ParamListHiddenComments,
 	paramExpr map[string]interface{},
-) ([]string, error) {
+) ([]*github.IssueComment, error) {
 	logE := logrus.WithFields(logrus.Fields{
 		"program": "github-comment",
 	})
@@ -177,11 +253,12 @@ func listHiddenComments( //nolint:funlen
 		"pr_number": param.PRNumber,
 	}).Debug("get comments")
 
-	nodeIDs := []string{}
+	hiddenComments := []*github.IssueComment{}
 	prg, err := exp.Compile(param.Condition)
 	if err != nil {
 		return nil, err //nolint:wrapcheck
 	}
+	lookup := newCommentLookup(comments, param.MetadataSchema)
 	for _, comment := range comments {
 		nodeID := comment.ID
 		// TODO remove these filters
@@ -192,15 +269,23 @@ func listHiddenComments( //nolint:funlen
 			}).Debug("exclude a comment")
 			continue
 		}
+		if !matchAuthorAssociation(comment, param.AuthorAssociations) {
+			logE.WithFields(logrus.Fields{
+				"node_id":            nodeID,
+				"author_association": comment.AuthorAssociation,
+			}).Debug("exclude a comment by the author association")
+			continue
+		}
 
 		metadata := map[string]interface{}{}
-		hasMeta := extractMetaFromComment(comment.Body, &metadata)
+		hasMeta := extractMetaFromComment(comment.Body, &metadata, param.MetadataSchema)
 		paramMap := map[string]interface{}{
 			"Comment": map[string]interface{}{
 				"Body": comment.Body,
 				// "CreatedAt": comment.CreatedAt,
-				"Meta":    metadata,
-				"HasMeta": hasMeta,
+				"Meta":              metadata,
+				"HasMeta":           hasMeta,
+				"AuthorAssociation": comment.AuthorAssociation,
 			},
 			"Commit": map[string]interface{}{
 				"Org":      param.Org,
@@ -210,6 +295,7 @@ func listHiddenComments( //nolint:funlen
 			},
 			"HideKey": param.HideKey,
 			"Vars":    param.Vars,
+			"comment": lookup,
 		}
 		for k, v := range paramExpr {
 			paramMap[k] = v
@@ -230,9 +316,23 @@ func listHiddenComments( //nolint:funlen
 		if !f {
 			continue
 		}
-		nodeIDs = append(nodeIDs, nodeID)
+		hiddenComments = append(hiddenComments, comment)
 	}
-	return nodeIDs, nil
+	return hiddenComments, nil
+}
+
+// matchAuthorAssociation returns true if the comment's author association is included in associations.
+// If associations is empty, true is returned.
+func matchAuthorAssociation(cmt *github.IssueComment, associations []string) bool {
+	if len(associations) == 0 {
+		return true
+	}
+	for _, association := range associations {
+		if strings.EqualFold(association, cmt.AuthorAssociation) {
+			return true
+		}
+	}
+	return false
 }
 
 func isExcludedComment(cmt *github.IssueComment, login string) bool {
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+func TestHideController_prependReason(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			ID:         "IC_1",
+			DatabaseID: 1,
+			Body:       "foo",
+		},
+		{
+			ID:         "IC_2",
+			DatabaseID: 2,
+			Body:       "bar",
+		},
+	}
+	data := []struct {
+		title          string
+		reasonTemplate string
+		exp            []*github.Comment
+	}{
+		{
+			title:          "normal",
+			reasonTemplate: "outdated by {{.SHA1}} ({{.HideKey}})",
+			exp: []*github.Comment{
+				{
+					PRNumber:  1,
+					Org:       "suzuki-shunsuke",
+					Repo:      "github-comment",
+					CommentID: 1,
+					Body:      "> folded: outdated by abc (default)\n\nfoo",
+				},
+				{
+					PRNumber:  1,
+					Org:       "suzuki-shunsuke",
+					Repo:      "github-comment",
+					CommentID: 2,
+					Body:      "> folded: outdated by abc (default)\n\nbar",
+				},
+			},
+		},
+		{
+			title:          "comments aren't edited if the template is invalid",
+			reasonTemplate: "{{.Foo",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{}
+			ctrl := &HideController{
+				GitHub:   gh,
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			ctrl.prependReason(context.Background(), &ParamListHiddenComments{
+				PRNumber: 1,
+				Org:      "suzuki-shunsuke",
+				Repo:     "github-comment",
+				SHA1:     "abc",
+				HideKey:  "default",
+			}, comments, d.reasonTemplate)
+			require.Equal(t, d.exp, gh.createdComments)
+		})
+	}
+}
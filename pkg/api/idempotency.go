@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// existsIdempotentComment returns true if a non-minimized comment with the idempotency key already exists.
+// This prevents duplicate comments when a step is retried after the comment was created.
+func existsIdempotentComment(ctx context.Context, gh GitHub, org, repo string, prNumber int, key string, schema map[string]string) (bool, error) {
+	if key == "" || prNumber == 0 {
+		return false, nil
+	}
+	comments, err := gh.ListComments(ctx, &github.PullRequest{
+		Org:      org,
+		Repo:     repo,
+		PRNumber: prNumber,
+	})
+	if err != nil {
+		return false, fmt.Errorf("list comments to check the idempotency key: %w", err)
+	}
+	for _, comment := range comments {
+		if comment.IsMinimized {
+			continue
+		}
+		metadata := map[string]interface{}{}
+		if !extractMetaFromComment(comment.Body, &metadata, schema) {
+			continue
+		}
+		if k, ok := metadata["IdempotencyKey"]; ok && k == key {
+			logrus.WithFields(logrus.Fields{
+				"idempotency_key": key,
+				"comment_id":      comment.DatabaseID,
+			}).Info("skip posting a comment because a comment with the same idempotency key already exists")
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_existsIdempotentComment(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title        string
+		key          string
+		prNumber     int
+		comments     []*github.IssueComment
+		exp          bool
+		expListCalls int
+	}{
+		{
+			title:    "no idempotency key",
+			prNumber: 1,
+			comments: []*github.IssueComment{
+				newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"\"} -->", false),
+			},
+		},
+		{
+			title: "no pull request",
+			key:   "foo",
+		},
+		{
+			title:    "a comment with the same key exists",
+			key:      "foo",
+			prNumber: 1,
+			comments: []*github.IssueComment{
+				newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"bar\"} -->", false),
+				newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"foo\"} -->", false),
+			},
+			exp:          true,
+			expListCalls: 1,
+		},
+		{
+			title:    "the comment with the same key is minimized",
+			key:      "foo",
+			prNumber: 1,
+			comments: []*github.IssueComment{
+				newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"foo\"} -->", true),
+			},
+			expListCalls: 1,
+		},
+		{
+			title:    "no comment with the same key",
+			key:      "foo",
+			prNumber: 1,
+			comments: []*github.IssueComment{
+				newFakeComment("octocat", "<!-- github-comment: {\"IdempotencyKey\":\"bar\"} -->", false),
+				newFakeComment("octocat", "foo", false),
+			},
+			expListCalls: 1,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{comments: d.comments}
+			exist, err := existsIdempotentComment(context.Background(), gh, "suzuki-shunsuke", "github-comment", d.prNumber, d.key, nil)
+			require.Nil(t, err)
+			require.Equal(t, d.exp, exist)
+			require.Equal(t, d.expListCalls, gh.listCalls)
+		})
+	}
+}
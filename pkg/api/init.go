@@ -16,6 +16,10 @@ const cfgTemplate = `---
 #     foo: hello
 # templates:
 #   header: "# {{.Org}}/{{.Repo}}"
+# footers:
+#   - when: Command.ExitCode != 0
+#     template: |
+#       {{template "link" .}}
 # post:
 #   default:
 #     template: |
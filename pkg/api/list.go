@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+// CommentInfo is a comment and the metadata which is parsed same as update and hide conditions.
+type CommentInfo struct {
+	ID          string                 `json:"id"`
+	DatabaseID  int64                  `json:"database_id"`
+	Author      string                 `json:"author"`
+	CreatedAt   string                 `json:"created_at"`
+	IsMinimized bool                   `json:"is_minimized"`
+	HasMeta     bool                   `json:"has_meta"`
+	Meta        map[string]interface{} `json:"meta"`
+}
+
+type ListController struct {
+	Stdout   io.Writer
+	GitHub   GitHub
+	Platform Platform
+	Config   *config.Config
+}
+
+// List outputs comments of the pull request and their metadata.
+// This helps to write update and hide conditions.
+func (ctrl *ListController) List(ctx context.Context, opts *option.ListOptions) error {
+	if ctrl.Platform != nil {
+		if err := ctrl.Platform.ComplementList(opts); err != nil {
+			return fmt.Errorf("failed to complement opts with platform built in environment variables: %w", err)
+		}
+	}
+	cfg := ctrl.Config
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+	if err := option.ValidateList(opts); err != nil {
+		return fmt.Errorf("opts is invalid: %w", err)
+	}
+
+	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+		Org:      opts.Org,
+		Repo:     opts.Repo,
+		PRNumber: opts.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list issue or pull request comments: %w", err)
+	}
+	infos := listCommentInfos(comments, cfg.MetadataSchema)
+
+	switch opts.OutputFormat {
+	case "", TemplatesOutputFormatText:
+		w := tabwriter.NewWriter(ctrl.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+		fmt.Fprintln(w, "ID\tAUTHOR\tMINIMIZED\tMETADATA")
+		for _, info := range infos {
+			meta := ""
+			if info.HasMeta {
+				b, err := json.Marshal(info.Meta)
+				if err != nil {
+					return fmt.Errorf("marshal metadata as JSON: %w", err)
+				}
+				meta = string(b)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.ID, info.Author, strconv.FormatBool(info.IsMinimized), meta)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("output comments: %w", err)
+		}
+		return nil
+	case TemplatesOutputFormatJSON:
+		encoder := json.NewEncoder(ctrl.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(infos); err != nil {
+			return fmt.Errorf("output comments as JSON: %w", err)
+		}
+		return nil
+	default:
+		return errors.New("invalid output format: " + opts.OutputFormat)
+	}
+}
+
+func listCommentInfos(comments []*github.IssueComment, schema map[string]string) []*CommentInfo {
+	infos := make([]*CommentInfo, len(comments))
+	for i, comment := range comments {
+		metadata := map[string]interface{}{}
+		hasMeta := extractMetaFromComment(comment.Body, &metadata, schema)
+		infos[i] = &CommentInfo{
+			ID:          comment.ID,
+			DatabaseID:  comment.DatabaseID,
+			Author:      comment.Author.Login,
+			CreatedAt:   comment.CreatedAt,
+			IsMinimized: comment.IsMinimized,
+			HasMeta:     hasMeta,
+			Meta:        metadata,
+		}
+	}
+	return infos
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func newListTestComments() []*github.IssueComment {
+	a := newFakeComment("octocat", "hello\n<!-- github-comment: {\"TemplateKey\":\"plan\",\"Vars\":{\"count\":\"3\"}} -->", false)
+	a.ID = "IC_1"
+	a.DatabaseID = 1
+	b := newFakeComment("foo", "lgtm", true)
+	b.ID = "IC_2"
+	b.DatabaseID = 2
+	return []*github.IssueComment{a, b}
+}
+
+func Test_listCommentInfos(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		schema map[string]string
+		exp    []*CommentInfo
+	}{
+		{
+			title: "normal",
+			exp: []*CommentInfo{
+				{
+					ID:         "IC_1",
+					DatabaseID: 1,
+					Author:     "octocat",
+					HasMeta:    true,
+					Meta: map[string]interface{}{
+						"TemplateKey": "plan",
+						"Vars":        map[string]interface{}{"count": "3"},
+					},
+				},
+				{
+					ID:          "IC_2",
+					DatabaseID:  2,
+					Author:      "foo",
+					IsMinimized: true,
+					Meta:        map[string]interface{}{},
+				},
+			},
+		},
+		{
+			title:  "metadata schema",
+			schema: map[string]string{"count": "number"},
+			exp: []*CommentInfo{
+				{
+					ID:         "IC_1",
+					DatabaseID: 1,
+					Author:     "octocat",
+					HasMeta:    true,
+					Meta: map[string]interface{}{
+						"TemplateKey": "plan",
+						"Vars":        map[string]interface{}{"count": 3.0},
+					},
+				},
+				{
+					ID:          "IC_2",
+					DatabaseID:  2,
+					Author:      "foo",
+					IsMinimized: true,
+					Meta:        map[string]interface{}{},
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, listCommentInfos(newListTestComments(), d.schema))
+		})
+	}
+}
+
+func TestListController_List(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title        string
+		prNumber     int
+		outputFormat string
+		exp          string
+		isErr        bool
+	}{
+		{
+			title:    "text",
+			prNumber: 1,
+			exp: `ID    AUTHOR   MINIMIZED  METADATA
+IC_1  octocat  false      {"TemplateKey":"plan","Vars":{"count":"3"}}
+IC_2  foo      true       
+`,
+		},
+		{
+			title:        "json",
+			prNumber:     1,
+			outputFormat: "json",
+			exp: `[
+  {
+    "id": "IC_1",
+    "database_id": 1,
+    "author": "octocat",
+    "created_at": "",
+    "is_minimized": false,
+    "has_meta": true,
+    "meta": {
+      "TemplateKey": "plan",
+      "Vars": {
+        "count": "3"
+      }
+    }
+  },
+  {
+    "id": "IC_2",
+    "database_id": 2,
+    "author": "foo",
+    "created_at": "",
+    "is_minimized": true,
+    "has_meta": false,
+    "meta": {}
+  }
+]
+`,
+		},
+		{
+			title:        "invalid output format",
+			prNumber:     1,
+			outputFormat: "yaml",
+			isErr:        true,
+		},
+		{
+			title: "pull request number is required",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			stdout := &bytes.Buffer{}
+			ctrl := &ListController{
+				Stdout: stdout,
+				GitHub: &fakeGitHub{comments: newListTestComments()},
+				Config: &config.Config{},
+			}
+			err := ctrl.List(context.Background(), &option.ListOptions{
+				Options: option.Options{
+					Org:      "suzuki-shunsuke",
+					Repo:     "github-comment",
+					Token:    "xxx",
+					PRNumber: d.prNumber,
+				},
+				OutputFormat: d.outputFormat,
+			})
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, stdout.String())
+		})
+	}
+}
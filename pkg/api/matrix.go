@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// getMatrix returns the matrix context of GitHub Actions.
+// The matrix context is read from the following sources in order:
+//
+// 1. the command line option --matrix-json
+// 2. the environment variable MATRIX_CONTEXT
+// 3. the environment variable GITHUB_COMMENT_MATRIX
+//
+// They should be set by `${{ toJSON(matrix) }}`.
+// If none of them is set, nil is returned.
+func getMatrix(matrixJSON string, getenv func(string) string) (interface{}, error) {
+	src := "matrix-json"
+	if matrixJSON == "" {
+		for _, k := range []string{"MATRIX_CONTEXT", "GITHUB_COMMENT_MATRIX"} {
+			if v := getenv(k); v != "" {
+				matrixJSON = v
+				src = k
+				break
+			}
+		}
+	}
+	if matrixJSON == "" {
+		return nil, nil //nolint:nilnil
+	}
+	var matrix interface{}
+	if err := json.Unmarshal([]byte(matrixJSON), &matrix); err != nil {
+		return nil, fmt.Errorf("parse %s as JSON: %w", src, err)
+	}
+	return matrix, nil
+}
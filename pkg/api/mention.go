@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+var (
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w` + "`" + `/@])@([a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:/([a-zA-Z0-9][a-zA-Z0-9_.-]*))?`)
+	// fencedCodePattern matches fenced code blocks. An unclosed code block continues to the end of the body
+	fencedCodePattern = regexp.MustCompile("(?ms)^ {0,3}(?:```.*?(?:^ {0,3}```|\\z)|~~~.*?(?:^ {0,3}~~~|\\z))")
+	inlineCodePattern = regexp.MustCompile("``.+?``|`[^`]+`")
+)
+
+type mention struct {
+	// Login is a user login or an organization name
+	Login string
+	// Team is a team slug. If the mention is a user, Team is empty
+	Team string
+}
+
+func (m *mention) String() string {
+	if m.Team == "" {
+		return "@" + m.Login
+	}
+	return "@" + m.Login + "/" + m.Team
+}
+
+// extractMentions returns unique mentions in the body.
+// Mentions in code blocks and code spans are ignored because GitHub doesn't notify them.
+func extractMentions(body string) []*mention {
+	body = fencedCodePattern.ReplaceAllString(body, "\n")
+	body = inlineCodePattern.ReplaceAllString(body, " ")
+	matches := mentionPattern.FindAllStringSubmatch(body, -1)
+	mentions := make([]*mention, 0, len(matches))
+	found := make(map[string]struct{}, len(matches))
+	for _, match := range matches {
+		m := &mention{
+			Login: match[1],
+			Team:  match[2],
+		}
+		if _, ok := found[m.String()]; ok {
+			continue
+		}
+		found[m.String()] = struct{}{}
+		mentions = append(mentions, m)
+	}
+	return mentions
+}
+
+// newMentionBody returns a function which renders the template without the command output.
+// Mentions in the command output aren't validated because the output such as logs isn't written by users.
+func (ctrl *ExecController) newMentionBody(tpl string, templates map[string]string, cmtParams *ExecCommentParams) func() (string, error) {
+	return func() (string, error) {
+		params := *cmtParams
+		params.Stdout = ""
+		params.Stderr = ""
+		params.CombinedOutput = ""
+		params.Previous = nil
+		body, err := ctrl.Renderer.Render(tpl, templates, &params)
+		if err != nil {
+			return "", fmt.Errorf("render a comment template without the output: %w", err)
+		}
+		return body, nil
+	}
+}
+
+func (ctrl *CommentController) mentionExists(ctx context.Context, m *mention) (bool, error) {
+	if m.Team == "" {
+		return ctrl.GitHub.UserExists(ctx, m.Login) //nolint:wrapcheck
+	}
+	return ctrl.GitHub.TeamExists(ctx, m.Login, m.Team) //nolint:wrapcheck
+}
+
+// validateMentions checks whether mentioned users and teams exist.
+// If ValidateMentions is "fail", an error is returned when unknown mentions are found.
+func (ctrl *CommentController) validateMentions(ctx context.Context, body string) error {
+	for _, m := range extractMentions(body) {
+		logE := logrus.WithFields(logrus.Fields{
+			"mention": m.String(),
+		})
+		f, err := ctrl.mentionExists(ctx, m)
+		if err != nil {
+			logE.WithError(err).Warn("check whether a mentioned user or team exists")
+			continue
+		}
+		if f {
+			continue
+		}
+		if ctrl.ValidateMentions == option.ValidateMentionsFail {
+			return errors.New("a mentioned user or team isn't found: " + m.String())
+		}
+		logE.Warn("a mentioned user or team isn't found")
+	}
+	return nil
+}
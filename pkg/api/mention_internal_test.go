@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+func Test_extractMentions(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		body  string
+		exp   []*mention
+	}{
+		{
+			title: "no mention",
+			body:  "hello",
+			exp:   []*mention{},
+		},
+		{
+			title: "users and teams",
+			body:  "@octocat please review. cc @my-org/sre-team, @octocat",
+			exp: []*mention{
+				{Login: "octocat"},
+				{Login: "my-org", Team: "sre-team"},
+			},
+		},
+		{
+			title: "email addresses and code spans are ignored",
+			body:  "foo@example.com `@octocat`",
+			exp:   []*mention{},
+		},
+		{
+			title: "mentions in code blocks are ignored",
+			body:  "@octocat\n```console\n$ npm install @types/node\n```\n~~~\n@my-org/sre-team\n~~~\n@hubot",
+			exp: []*mention{
+				{Login: "octocat"},
+				{Login: "hubot"},
+			},
+		},
+		{
+			title: "an unclosed code block continues to the end",
+			body:  "@octocat\n```\n@hubot",
+			exp: []*mention{
+				{Login: "octocat"},
+			},
+		},
+		{
+			title: "mentions in code spans are ignored",
+			body:  "run `npm i @types/node` and ``echo `@hubot` `` then ping @octocat",
+			exp: []*mention{
+				{Login: "octocat"},
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, extractMentions(d.body))
+		})
+	}
+}
+
+func TestCommentController_Post_mentionBody(t *testing.T) {
+	t.Parallel()
+	exec := &ExecController{
+		Renderer: &template.Renderer{},
+	}
+	data := []struct {
+		title       string
+		mentionBody func() (string, error)
+		isErr       bool
+	}{
+		{
+			title: "the whole body is validated",
+			isErr: true,
+		},
+		{
+			title: "mentions in the command output aren't validated",
+			mentionBody: exec.newMentionBody("cc @octocat\n{{.CombinedOutput}}", nil, &ExecCommentParams{
+				CombinedOutput: "pinged by @ghost",
+			}),
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{users: []string{"octocat"}}
+			ctrl := &CommentController{
+				GitHub:           gh,
+				Expr:             &expr.Expr{},
+				ValidateMentions: option.ValidateMentionsFail,
+			}
+			err := ctrl.Post(context.Background(), &github.Comment{
+				Org:         "suzuki-shunsuke",
+				Repo:        "github-comment",
+				PRNumber:    1,
+				Body:        "cc @octocat\npinged by @ghost",
+				MentionBody: d.mentionBody,
+			}, nil)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.NotNil(t, gh.createdComment)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+// coerceMetadataValue converts the value to the type declared in metadata_schema.
+func coerceMetadataValue(v interface{}, typ string) (interface{}, error) {
+	switch typ {
+	case config.MetadataTypeString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprint(v), nil
+	case config.MetadataTypeNumber:
+		switch a := v.(type) {
+		case float64:
+			return a, nil
+		case int:
+			return float64(a), nil
+		case string:
+			f, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse a value as a number: %w", err)
+			}
+			return f, nil
+		}
+		return nil, fmt.Errorf("a value can't be converted to a number: %v", v)
+	case config.MetadataTypeBoolean:
+		switch a := v.(type) {
+		case bool:
+			return a, nil
+		case string:
+			b, err := strconv.ParseBool(a)
+			if err != nil {
+				return nil, fmt.Errorf("parse a value as a boolean: %w", err)
+			}
+			return b, nil
+		}
+		return nil, fmt.Errorf("a value can't be converted to a boolean: %v", v)
+	default:
+		return nil, fmt.Errorf("unknown type of metadata_schema: %s", typ)
+	}
+}
+
+// validateMetadataVars converts embedded variables to types declared in metadata_schema.
+// If a value can't be converted, an error is returned.
+func validateMetadataVars(vars map[string]interface{}, schema map[string]string) error {
+	for name, typ := range schema {
+		v, ok := vars[name]
+		if !ok {
+			continue
+		}
+		a, err := coerceMetadataValue(v, typ)
+		if err != nil {
+			return fmt.Errorf("the embedded variable %s is invalid against metadata_schema: %w", name, err)
+		}
+		vars[name] = a
+	}
+	return nil
+}
+
+// coerceMetadata converts embedded variables of the extracted metadata to types declared in metadata_schema.
+// Values which can't be converted are kept as they are.
+func coerceMetadata(data map[string]interface{}, schema map[string]string) {
+	if len(schema) == 0 {
+		return
+	}
+	vars, ok := data["Vars"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name, typ := range schema {
+		v, ok := vars[name]
+		if !ok {
+			continue
+		}
+		a, err := coerceMetadataValue(v, typ)
+		if err != nil {
+			logrus.WithError(err).WithField("name", name).Debug("coerce an embedded variable")
+			continue
+		}
+		vars[name] = a
+	}
+}
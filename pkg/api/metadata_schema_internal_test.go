@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_coerceMetadataValue(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		value interface{}
+		typ   string
+		exp   interface{}
+		isErr bool
+	}{
+		{
+			title: "string to number",
+			value: "3",
+			typ:   "number",
+			exp:   float64(3),
+		},
+		{
+			title: "number",
+			value: float64(3),
+			typ:   "number",
+			exp:   float64(3),
+		},
+		{
+			title: "invalid number",
+			value: "foo",
+			typ:   "number",
+			isErr: true,
+		},
+		{
+			title: "string to boolean",
+			value: "true",
+			typ:   "boolean",
+			exp:   true,
+		},
+		{
+			title: "number to string",
+			value: float64(3),
+			typ:   "string",
+			exp:   "3",
+		},
+		{
+			title: "unknown type",
+			value: "foo",
+			typ:   "foo",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			v, err := coerceMetadataValue(d.value, d.typ)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, v)
+		})
+	}
+}
+
+func Test_metadataSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+	schema := map[string]string{
+		"count":   config.MetadataTypeNumber,
+		"success": config.MetadataTypeBoolean,
+		"name":    config.MetadataTypeString,
+	}
+	data := []struct {
+		title    string
+		vars     map[string]interface{}
+		validate bool
+		exp      map[string]interface{}
+	}{
+		{
+			title: "values are converted when they are embedded",
+			vars: map[string]interface{}{
+				"count":   "3",
+				"success": "true",
+				"name":    1,
+				"other":   "1",
+			},
+			validate: true,
+			exp: map[string]interface{}{
+				"count":   3.0,
+				"success": true,
+				"name":    "1",
+				"other":   "1",
+			},
+		},
+		{
+			title: "values embedded before the schema is configured are coerced when they are read",
+			vars: map[string]interface{}{
+				"count":   "3",
+				"success": "false",
+			},
+			exp: map[string]interface{}{
+				"count":   3.0,
+				"success": false,
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			if d.validate {
+				require.Nil(t, validateMetadataVars(d.vars, schema))
+			}
+			embedded, err := metadata.Convert(map[string]interface{}{
+				"TemplateKey":    "default",
+				"IdempotencyKey": "foo",
+				"Vars":           d.vars,
+			})
+			require.Nil(t, err)
+			body := "hello" + embedded
+			m := map[string]interface{}{}
+			require.True(t, extractMetaFromComment(body, &m, schema))
+			require.Equal(t, d.exp, m["Vars"])
+
+			// the metadata is read with the schema everywhere
+			exist, err := existsIdempotentComment(context.Background(), &fakeGitHub{
+				comments: []*github.IssueComment{{Body: body}},
+			}, "suzuki-shunsuke", "github-comment", 1, "foo", schema)
+			require.Nil(t, err)
+			require.True(t, exist)
+			require.True(t, isPrunedComment(&github.IssueComment{Body: body}, "", "default", schema))
+
+			out := filepath.Join(t.TempDir(), "metadata.json")
+			ctrl := &CommentController{
+				MetadataOut:    out,
+				MetadataSchema: schema,
+			}
+			require.Nil(t, ctrl.writeMetadata(&github.Comment{Metadata: embedded}))
+			b, err := os.ReadFile(out)
+			require.Nil(t, err)
+			written := map[string]interface{}{}
+			require.Nil(t, json.Unmarshal(b, &written))
+			require.Equal(t, d.exp, written["Vars"])
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+const (
+	// TruncateOutputLargest truncates the largest output among Stdout, Stderr, and CombinedOutput
+	TruncateOutputLargest = "largest"
+	// maxFitAttempts is the max number of times the body is re-rendered to fit within the limit
+	maxFitAttempts = 3
+)
+
+func validateTruncateOutput(field string) error {
+	switch field {
+	case "", TruncateOutputLargest, "Stdout", "Stderr", "CombinedOutput":
+		return nil
+	}
+	return fmt.Errorf(`invalid truncate_output: %s. It must be either "largest", "Stdout", "Stderr", or "CombinedOutput"`, field)
+}
+
+// outputField returns the pointer to the output field which is truncated.
+func outputField(params *ExecCommentParams, field string) *string {
+	switch field {
+	case "Stdout":
+		return &params.Stdout
+	case "Stderr":
+		return &params.Stderr
+	case "CombinedOutput":
+		return &params.CombinedOutput
+	}
+	// largest
+	ret := &params.CombinedOutput
+	if len(params.Stdout) > len(*ret) {
+		ret = &params.Stdout
+	}
+	if len(params.Stderr) > len(*ret) {
+		ret = &params.Stderr
+	}
+	return ret
+}
+
+// newFitBody returns a function which renders the template with the output truncated
+// so that the body fits within the given length.
+// The length of the non-output portion is measured by rendering the template with the output blanked,
+// and the output is truncated to the remaining budget.
+func (ctrl *ExecController) newFitBody(tpl string, templates map[string]string, cmtParams *ExecCommentParams, field string) func(int) (string, error) {
+	return func(limit int) (string, error) {
+		params := *cmtParams
+		output := outputField(&params, field)
+		original := *output
+		*output = ""
+		base, err := ctrl.Renderer.Render(tpl, templates, &params)
+		if err != nil {
+			return "", fmt.Errorf("render a comment template without the output: %w", err)
+		}
+		budget := limit - github.CommentLength(base)
+		var body string
+		for i := 0; i < maxFitAttempts; i++ {
+			if budget < 0 {
+				budget = 0
+			}
+			*output = truncateBody(original, config.TooLongStrategyTruncateMiddle, budget)
+			body, err = ctrl.Renderer.Render(tpl, templates, &params)
+			if err != nil {
+				return "", fmt.Errorf("render a comment template with the truncated output: %w", err)
+			}
+			if github.CommentLength(body) <= limit {
+				return body, nil
+			}
+			// the output may be escaped or embedded multiple times
+			budget -= github.CommentLength(body) - limit
+		}
+		return body, nil
+	}
+}
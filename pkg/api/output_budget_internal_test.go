@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+func TestExecController_newFitBody(t *testing.T) {
+	t.Parallel()
+	ctrl := &ExecController{
+		Renderer: &template.Renderer{},
+	}
+	fitBody := ctrl.newFitBody("header\n{{.CombinedOutput}}\nfooter", nil, &ExecCommentParams{
+		Stdout:         "foo",
+		CombinedOutput: strings.Repeat("a", 300),
+	}, TruncateOutputLargest)
+	body, err := fitBody(150)
+	require.Nil(t, err)
+	require.True(t, len(body) <= 150)
+	require.True(t, strings.HasPrefix(body, "header\n"))
+	require.True(t, strings.HasSuffix(body, "\nfooter"))
+	require.True(t, strings.Contains(body, truncatedMarker))
+}
+
+func TestExecController_getComment_truncateOutput(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title          string
+		truncateOutput string
+		exp            bool
+	}{
+		{
+			title: "truncate_output isn't set",
+		},
+		{
+			title:          "truncate_output is set",
+			truncateOutput: TruncateOutputLargest,
+			exp:            true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{
+				GitHub:   &fakeGitHub{},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			cmt, f, err := ctrl.getComment(context.Background(), []*config.ExecConfig{
+				{
+					When:           "true",
+					Template:       "{{.CombinedOutput}}",
+					TruncateOutput: d.truncateOutput,
+				},
+			}, &ExecCommentParams{
+				Org:            "suzuki-shunsuke",
+				Repo:           "github-comment",
+				PRNumber:       1,
+				TemplateKey:    "test",
+				CombinedOutput: "foo",
+				Vars:           map[string]interface{}{},
+			}, nil)
+			require.Nil(t, err)
+			require.True(t, f)
+			require.Equal(t, d.exp, cmt.FitBody != nil)
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 	"github.com/suzuki-shunsuke/github-comment/pkg/github"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
 	"github.com/suzuki-shunsuke/github-comment/pkg/template"
@@ -22,19 +24,27 @@ type PostController struct {
 	// If thre is the standard input, it is treated as the comment template
 	HasStdin func() bool
 	Stdin    io.Reader
+	// Stdout is where the URL of the posted comment is written
+	Stdout   io.Writer
 	Stderr   io.Writer
 	GitHub   GitHub
 	Renderer Renderer
 	Platform Platform
 	Config   *config.Config
 	Expr     Expr
+	// Version is github-comment's version
+	Version string
 }
 
 func (ctrl *PostController) Post(ctx context.Context, opts *option.PostOptions) error {
+	ctrl.GitHub = newCommentsCache(ctrl.GitHub)
 	cmt, err := ctrl.getCommentParams(ctx, opts)
 	if err != nil {
 		return err
 	}
+	if cmt == nil {
+		return nil
+	}
 	logrus.WithFields(logrus.Fields{
 		"org":       cmt.Org,
 		"repo":      cmt.Repo,
@@ -42,32 +52,63 @@ func (ctrl *PostController) Post(ctx context.Context, opts *option.PostOptions)
 		"sha":       cmt.SHA1,
 	}).Debug("comment meta data")
 
+	var matrix interface{}
+	if opts.Summary != "" {
+		// the error has already been checked by getCommentParams
+		matrix, _ = getMatrix(opts.MatrixJSON, ctrl.Getenv)
+	}
+
 	cmtCtrl := CommentController{
-		GitHub: ctrl.GitHub,
-		Expr:   ctrl.Expr,
-		Getenv: ctrl.Getenv,
+		GitHub:           ctrl.GitHub,
+		Expr:             ctrl.Expr,
+		Getenv:           ctrl.Getenv,
+		ValidateMentions: opts.ValidateMentions,
+		MetadataOut:      opts.MetadataOut,
+		Version:          ctrl.Version,
+		RedactPatterns:   ctrl.Config.RedactPatterns,
+		CommentLimit:     getCommentLimit(opts.PRCommentLimit, ctrl.Config.MaxCommentsPerPR),
+		Cooldown:         newCooldown(opts.CooldownFile, opts.Cooldown, opts.TemplateKey, opts.Target),
+		Summary:          newSummary(opts.Summary, opts.TemplateKey, opts.Target, matrix),
+		MetadataSchema:   ctrl.Config.MetadataSchema,
+	}
+	if err := cmtCtrl.Post(ctx, cmt, nil); err != nil {
+		return err
+	}
+	// the URL is empty if the comment isn't posted because of the cooldown
+	if cmt.URL != "" && !opts.Silent && !ctrl.Config.Silent && ctrl.Stdout != nil {
+		fmt.Fprintln(ctrl.Stdout, cmt.URL)
 	}
-	return cmtCtrl.Post(ctx, cmt, nil)
+	return nil
+}
+
+// getUpdatedComment returns the comment which matches with the update condition.
+// If no comment matches, nil is returned.
+func (ctrl *PostController) getUpdatedComment(ctx context.Context, cmt *github.Comment, opts *option.PostOptions) (*github.IssueComment, error) {
+	return searchUpdatedComment(ctx, ctrl.GitHub, ctrl.Expr, ctrl.Config, cmt, opts.UpdateCondition, opts.EditWithin)
 }
 
-func (ctrl *PostController) setUpdatedCommentID(ctx context.Context, cmt *github.Comment, updateCondition string) error { //nolint:funlen
-	prg, err := ctrl.Expr.Compile(updateCondition)
+// searchUpdatedComment lists comments of the pull request and returns the comment which matches with the update condition.
+// If no comment matches, nil is returned.
+func searchUpdatedComment(
+	ctx context.Context, gh GitHub, ex Expr, cfg *config.Config, cmt *github.Comment, condition string, editWithin time.Duration,
+) (*github.IssueComment, error) {
+	prg, err := ex.Compile(condition)
 	if err != nil {
-		return err //nolint:wrapcheck
+		return nil, err //nolint:wrapcheck
 	}
 
-	login, err := ctrl.GitHub.GetAuthenticatedUser(ctx)
+	login, err := gh.GetAuthenticatedUser(ctx)
 	if err != nil {
 		logrus.WithError(err).Warn("get an authenticated user")
 	}
 
-	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+	comments, err := gh.ListComments(ctx, &github.PullRequest{
 		Org:      cmt.Org,
 		Repo:     cmt.Repo,
 		PRNumber: cmt.PRNumber,
 	})
 	if err != nil {
-		return fmt.Errorf("list issue or pull request comments: %w", err)
+		return nil, fmt.Errorf("list issue or pull request comments: %w", err)
 	}
 	logrus.WithFields(logrus.Fields{
 		"org":       cmt.Org,
@@ -75,38 +116,121 @@ func (ctrl *PostController) setUpdatedCommentID(ctx context.Context, cmt *github
 		"pr_number": cmt.PRNumber,
 	}).Debug("get comments")
 
+	return FindUpdatedComment(prg, cmt, comments, &ParamFindUpdatedComment{
+		Login:      login,
+		EditWithin: editWithin,
+		Now:        time.Now(),
+		Condition:  condition,
+
+		AuthorAssociations: cfg.AuthorAssociations,
+		MetadataSchema:     cfg.MetadataSchema,
+	}), nil
+}
+
+// setUpdatedCommentID sets the comment id which is updated.
+// If repin is enabled, the comment is replaced with a new comment instead.
+func setUpdatedCommentID(cmt *github.Comment, comment *github.IssueComment, opts *option.PostOptions) {
+	if opts.Repin && isRepinnable(comment, opts.RepinCooldown, time.Now()) {
+		// delete the old comment after posting a new comment so that the comment moves to the bottom
+		cmt.ReplacedCommentID = comment.DatabaseID
+		return
+	}
+	cmt.CommentID = comment.DatabaseID
+}
+
+// mergeVarsFromComment merges the embedded Vars of the comment into vars.
+// The merged variables have the lowest precedence, so existing variables aren't overwritten.
+// Values are coerced by the metadata schema.
+func mergeVarsFromComment(vars map[string]interface{}, body string, schema map[string]string) {
+	metadata := map[string]interface{}{}
+	if !extractMetaFromComment(body, &metadata, schema) {
+		return
+	}
+	embeddedVars, ok := metadata["Vars"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range embeddedVars {
+		if _, ok := vars[k]; !ok {
+			vars[k] = v
+		}
+	}
+}
+
+// isRepinnable returns true if the comment was created before the cooldown.
+// The cooldown prevents deleting and creating comments too frequently.
+func isRepinnable(comment *github.IssueComment, cooldown time.Duration, now time.Time) bool {
+	if cooldown <= 0 {
+		return true
+	}
+	return !isCreatedWithin(comment, cooldown, now)
+}
+
+// ParamFindUpdatedComment is parameters of FindUpdatedComment.
+type ParamFindUpdatedComment struct {
+	// Login is the login of the authenticated user. If it's set, other users' comments are ignored
+	Login string
+	// EditWithin ignores comments created before Now - EditWithin. If it's zero, comments aren't ignored
+	EditWithin time.Duration
+	Now        time.Time
+	// Condition is the update condition. It's used only for logging because the compiled program is given
+	Condition string
+	// AuthorAssociations narrows comments to ones whose author associations are included
+	AuthorAssociations []string
+	// MetadataSchema declares types of embedded variables
+	MetadataSchema map[string]string
+}
+
+// FindUpdatedComment returns the latest comment which matches with the update condition.
+// If no comment matches, nil is returned.
+// The parameter map is reused across comments to reduce allocations because this is a hot loop.
+// It doesn't call GitHub API, so the evaluation over comments can be benchmarked by go test -bench.
+func FindUpdatedComment(prg expr.Program, cmt *github.Comment, comments []*github.IssueComment, param *ParamFindUpdatedComment) *github.IssueComment {
+	commentParam := make(map[string]interface{}, 4) //nolint:gomnd
+	paramMap := map[string]interface{}{
+		"Comment": commentParam,
+		"Commit": map[string]interface{}{
+			"Org":      cmt.Org,
+			"Repo":     cmt.Repo,
+			"PRNumber": cmt.PRNumber,
+			"SHA1":     cmt.SHA1,
+		},
+		"Vars":    cmt.Vars,
+		"comment": newCommentLookup(comments, param.MetadataSchema),
+	}
+	debug := logrus.IsLevelEnabled(logrus.DebugLevel)
+	var ret *github.IssueComment
 	for _, comnt := range comments {
 		if comnt.IsMinimized {
 			// ignore minimized comments
 			continue
 		}
-		if login != "" && comnt.Author.Login != login {
+		if param.Login != "" && comnt.Author.Login != param.Login {
 			// ignore other users' comments
 			continue
 		}
+		if param.EditWithin > 0 && !isCreatedWithin(comnt, param.EditWithin, param.Now) {
+			// ignore old comments
+			continue
+		}
+		if !matchAuthorAssociation(comnt, param.AuthorAssociations) {
+			continue
+		}
 
 		metadata := map[string]interface{}{}
-		hasMeta := extractMetaFromComment(comnt.Body, &metadata)
-		paramMap := map[string]interface{}{
-			"Comment": map[string]interface{}{
-				"Body":    comnt.Body,
-				"Meta":    metadata,
-				"HasMeta": hasMeta,
-			},
-			"Commit": map[string]interface{}{
-				"Org":      cmt.Org,
-				"Repo":     cmt.Repo,
-				"PRNumber": cmt.PRNumber,
-				"SHA1":     cmt.SHA1,
-			},
-			"Vars": cmt.Vars,
-		}
+		hasMeta := extractMetaFromComment(comnt.Body, &metadata, param.MetadataSchema)
+		commentParam["Body"] = comnt.Body
+		commentParam["Meta"] = metadata
+		commentParam["HasMeta"] = hasMeta
+		commentParam["AuthorAssociation"] = comnt.AuthorAssociation
 
-		logrus.WithFields(logrus.Fields{
-			"node_id":   comnt.ID,
-			"condition": updateCondition,
-			"param":     paramMap,
-		}).Debug("judge whether an existing comment is ready for editing")
+		if debug {
+			logrus.WithFields(logrus.Fields{
+				"node_id":   comnt.ID,
+				"condition": param.Condition,
+				"param":     paramMap,
+			}).Debug("judge whether an existing comment is ready for editing")
+		}
 		f, err := prg.Run(paramMap)
 		if err != nil {
 			logrus.WithError(err).WithFields(logrus.Fields{
@@ -117,9 +241,39 @@ func (ctrl *PostController) setUpdatedCommentID(ctx context.Context, cmt *github
 		if !f {
 			continue
 		}
-		cmt.CommentID = comnt.DatabaseID
+		ret = comnt
 	}
-	return nil
+	return ret
+}
+
+// isCreatedWithin returns true if the comment was created within the duration.
+func isCreatedWithin(comment *github.IssueComment, d time.Duration, now time.Time) bool {
+	createdAt, err := time.Parse(time.RFC3339, comment.CreatedAt)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"node_id":    comment.ID,
+			"created_at": comment.CreatedAt,
+		}).Warn("parse the comment's createdAt")
+		return false
+	}
+	return now.Sub(createdAt) <= d
+}
+
+// isUpdatedWithin returns true if the comment was updated within d.
+// If the comment's updatedAt is unknown, createdAt is used instead.
+func isUpdatedWithin(comment *github.IssueComment, d time.Duration, now time.Time) bool {
+	if comment.UpdatedAt == "" {
+		return isCreatedWithin(comment, d, now)
+	}
+	updatedAt, err := time.Parse(time.RFC3339, comment.UpdatedAt)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"node_id":    comment.ID,
+			"updated_at": comment.UpdatedAt,
+		}).Warn("parse the comment's updatedAt")
+		return false
+	}
+	return now.Sub(updatedAt) <= d
 }
 
 // Reader is API to find and read the configuration file of github-comment
@@ -142,15 +296,29 @@ type PostTemplateParams struct {
 	SHA1        string
 	TemplateKey string
 	Vars        map[string]interface{}
+	// Matrix is the matrix context of GitHub Actions
+	Matrix interface{}
+	// PreviousBody is the body of the comment which is updated. The embedded metadata and footers are removed.
+	// It's empty if no comment is updated
+	PreviousBody string
+	// CommentExists is true if a comment matching the update condition exists
+	CommentExists bool
 }
 
 type Platform interface {
 	ComplementPost(opts *option.PostOptions) error
 	ComplementExec(opts *option.ExecOptions) error
 	ComplementHide(opts *option.HideOptions) error
+	ComplementPrune(opts *option.PruneOptions) error
+	ComplementSuggest(opts *option.SuggestOptions) error
+	ComplementResolveThreads(opts *option.ResolveThreadsOptions) error
+	ComplementReact(opts *option.ReactOptions) error
+	ComplementList(opts *option.ListOptions) error
 	CI() string
 }
 
+// getCommentParams returns the comment which is posted.
+// If comment-if isn't matched or a comment with the same idempotency key exists, nil is returned.
 func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.PostOptions) (*github.Comment, error) { //nolint:funlen,cyclop,gocognit
 	if ctrl.Platform != nil {
 		if err := ctrl.Platform.ComplementPost(opts); err != nil {
@@ -158,6 +326,32 @@ func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.P
 		}
 	}
 
+	cfg := ctrl.Config
+
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if opts.TemplateKeyFromGit {
+		key, err := templateKeyFromGit(ctx, cfg.TemplateKeyRules, ctrl.Getenv)
+		if err != nil {
+			return nil, err
+		}
+		if key != "" {
+			opts.TemplateKey = key
+		}
+	}
+
+	// validate the repository before GitHub API is called
+	if err := option.ValidateRepository(&opts.Options); err != nil {
+		return nil, fmt.Errorf("opts is invalid: %w", err)
+	}
+
 	if opts.PRNumber == 0 && opts.SHA1 != "" {
 		prNum, err := ctrl.GitHub.PRNumberWithSHA(ctx, opts.Org, opts.Repo, opts.SHA1)
 		if err != nil {
@@ -172,6 +366,16 @@ func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.P
 		}
 	}
 
+	if err := validateRequirePR(&opts.Options); err != nil {
+		return nil, err
+	}
+
+	if matched, err := checkPRBase(ctx, ctrl.GitHub, opts.Org, opts.Repo, opts.PRNumber, opts.PRBase); err != nil {
+		return nil, err
+	} else if !matched {
+		return nil, nil //nolint:nilnil
+	}
+
 	if opts.Template == "" && opts.StdinTemplate {
 		tpl, err := ctrl.readTemplateFromStdin()
 		if err != nil {
@@ -180,17 +384,6 @@ func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.P
 		opts.Template = tpl
 	}
 
-	cfg := ctrl.Config
-
-	if cfg.Base != nil {
-		if opts.Org == "" {
-			opts.Org = cfg.Base.Org
-		}
-		if opts.Repo == "" {
-			opts.Repo = cfg.Base.Repo
-		}
-	}
-
 	if err := option.ValidatePost(opts); err != nil {
 		return nil, fmt.Errorf("opts is invalid: %w", err)
 	}
@@ -203,48 +396,185 @@ func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.P
 		opts.Template = tpl.Template
 		opts.TemplateForTooLong = tpl.TemplateForTooLong
 		opts.EmbeddedVarNames = tpl.EmbeddedVarNames
+		opts.ExtraMetadata = tpl.Metadata
 		if opts.UpdateCondition == "" {
 			opts.UpdateCondition = tpl.UpdateCondition
 		}
+		if tpl.MinInterval != "" {
+			d, err := time.ParseDuration(tpl.MinInterval)
+			if err != nil {
+				return nil, fmt.Errorf("parse min_interval as a duration: %w", err)
+			}
+			opts.MinInterval = d
+		}
+	}
+	if opts.Sticky != "" {
+		opts.UpdateCondition = stickyUpdateCondition(opts.Sticky, "")
+	} else if opts.UpdateCondition == "" && !opts.NoMetadata && cfg.UpdateKey != "" {
+		// update_key is shared by all template keys,
+		// so comments of different template keys are updated separately
+		opts.Sticky = cfg.UpdateKey
+		opts.UpdateCondition = stickyUpdateCondition(opts.Sticky, opts.TemplateKey)
+	}
+
+	matrix, err := getMatrix(opts.MatrixJSON, ctrl.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	target, derived := getTarget(opts.Target, opts.Vars, cfg.AutoTarget, ctrl.Getenv, matrix)
+	opts.Target = target
+	if derived && target != "" && opts.UpdateCondition == "" {
+		opts.UpdateCondition = targetUpdateCondition(target)
+	}
+	if opts.UpdateCondition == "" {
+		opts.UpdateCondition = cfg.DefaultUpdateCondition
 	}
 
 	if cfg.Vars == nil {
-		cfg.Vars = make(map[string]interface{}, len(opts.Vars))
+		cfg.Vars = make(map[string]interface{}, len(opts.Vars)+len(opts.StructuredVars))
+	}
+	for k, v := range opts.StructuredVars {
+		cfg.Vars[k] = v
 	}
 	for k, v := range opts.Vars {
 		cfg.Vars[k] = v
 	}
+	if err := interpolateVars(cfg.Vars, opts.AllowUndefinedVars); err != nil {
+		return nil, fmt.Errorf("interpolate variables: %w", err)
+	}
 
-	ci := ""
-	if ctrl.Platform != nil {
-		ci = ctrl.Platform.CI()
+	if opts.CommentID != 0 && opts.UpdateCondition != "" {
+		logrus.WithFields(logrus.Fields{
+			"comment_id":       opts.CommentID,
+			"update_condition": opts.UpdateCondition,
+		}).Debug("the update condition is ignored because the comment id is given")
 	}
-	templates := template.GetTemplates(&template.ParamGetTemplates{
-		Templates: cfg.Templates,
-		CI:        ci,
-	})
-	tpl, err := ctrl.Renderer.Render(opts.Template, templates, PostTemplateParams{
+	if opts.CommentID == 0 && opts.UpdateCondition != "" && opts.PRNumber == 0 {
+		logrus.WithFields(logrus.Fields{
+			"update_condition": opts.UpdateCondition,
+			"sha":              opts.SHA1,
+		}).Warn("the update condition is ignored because no pull request is found. Please set --pr to update a comment")
+	}
+	if opts.Once && opts.UpdateCondition == "" {
+		return nil, errors.New("once requires an update condition. Please set it by --update-condition, --sticky, or the configuration file")
+	}
+	var updatedComment *github.IssueComment
+	if opts.CommentID == 0 && opts.UpdateCondition != "" && opts.PRNumber != 0 {
+		// resolve the updated comment before evaluating comment-if and rendering templates
+		// so that commentExists can be referred and the embedded vars can be merged
+		a, err := ctrl.getUpdatedComment(ctx, &github.Comment{
+			Org:         opts.Org,
+			Repo:        opts.Repo,
+			PRNumber:    opts.PRNumber,
+			SHA1:        opts.SHA1,
+			TemplateKey: opts.TemplateKey,
+			Vars:        cfg.Vars,
+		}, opts)
+		if err != nil {
+			return nil, err
+		}
+		updatedComment = a
+	}
+	if updatedComment != nil && opts.MinInterval > 0 && isUpdatedWithin(updatedComment, opts.MinInterval, time.Now()) {
+		logrus.WithFields(logrus.Fields{
+			"min_interval": opts.MinInterval,
+			"node_id":      updatedComment.ID,
+			"updated_at":   updatedComment.UpdatedAt,
+		}).Debug("skip posting a comment because the matched comment was updated within min_interval")
+		return nil, nil //nolint:nilnil
+	}
+	if updatedComment != nil && opts.Once {
+		logrus.WithFields(logrus.Fields{
+			"update_condition": opts.UpdateCondition,
+			"node_id":          updatedComment.ID,
+		}).Debug("skip posting a comment because a comment matching the update condition already exists")
+		return nil, nil //nolint:nilnil
+	}
+
+	if opts.CommentIf != "" {
+		f, err := ctrl.Expr.Match(opts.CommentIf, map[string]interface{}{
+			"Commit": map[string]interface{}{
+				"Org":      opts.Org,
+				"Repo":     opts.Repo,
+				"PRNumber": opts.PRNumber,
+				"SHA1":     opts.SHA1,
+			},
+			"TemplateKey": opts.TemplateKey,
+			"Vars":        cfg.Vars,
+			"Matrix":      matrix,
+			"Env":         ctrl.Getenv,
+			// commentExists is true if a comment matching the update condition exists
+			"commentExists": updatedComment != nil,
+			"comment": newLazyCommentLookup(ctx, ctrl.GitHub, &github.PullRequest{
+				Org:      opts.Org,
+				Repo:     opts.Repo,
+				PRNumber: opts.PRNumber,
+			}, cfg.MetadataSchema),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("evaluate comment-if: %w", err)
+		}
+		if !f {
+			logrus.WithFields(logrus.Fields{
+				"comment_if": opts.CommentIf,
+			}).Info("skip posting a comment because comment-if isn't matched")
+			return nil, nil //nolint:nilnil
+		}
+	}
+
+	// the comment given by --comment-id is updated, so the idempotency key isn't checked
+	if opts.CommentID == 0 {
+		if exist, err := existsIdempotentComment(ctx, ctrl.GitHub, opts.Org, opts.Repo, opts.PRNumber, opts.IdempotencyKey, cfg.MetadataSchema); err != nil {
+			return nil, err
+		} else if exist {
+			return nil, nil //nolint:nilnil
+		}
+	}
+
+	if opts.MergeVarsFromComment && updatedComment != nil {
+		mergeVarsFromComment(cfg.Vars, updatedComment.Body, cfg.MetadataSchema)
+	}
+
+	tplParams := PostTemplateParams{
 		PRNumber:    opts.PRNumber,
 		Org:         opts.Org,
 		Repo:        opts.Repo,
 		SHA1:        opts.SHA1,
 		TemplateKey: opts.TemplateKey,
 		Vars:        cfg.Vars,
+		Matrix:      matrix,
+	}
+	if updatedComment != nil {
+		tplParams.PreviousBody = stripMetadata(updatedComment.Body)
+		tplParams.CommentExists = true
+	}
+
+	ci := ""
+	if ctrl.Platform != nil {
+		ci = ctrl.Platform.CI()
+	}
+	if opts.Lang == "" {
+		opts.Lang = cfg.Lang
+	}
+	templates := template.GetTemplates(&template.ParamGetTemplates{
+		Templates: cfg.Templates,
+		CI:        ci,
+		Lang:      opts.Lang,
 	})
+	tpl, err := ctrl.Renderer.Render(opts.Template, templates, tplParams)
 	if err != nil {
 		return nil, fmt.Errorf("render a template for post: %w", err)
 	}
-	tplForTooLong, err := ctrl.Renderer.Render(opts.TemplateForTooLong, templates, PostTemplateParams{
-		PRNumber:    opts.PRNumber,
-		Org:         opts.Org,
-		Repo:        opts.Repo,
-		SHA1:        opts.SHA1,
-		TemplateKey: opts.TemplateKey,
-		Vars:        cfg.Vars,
-	})
+	tplForTooLong, err := ctrl.Renderer.Render(opts.TemplateForTooLong, templates, tplParams)
 	if err != nil {
 		return nil, fmt.Errorf("render a template template_for_too_long for post: %w", err)
 	}
+	var fitBody func(int) (string, error)
+	if opts.Append && updatedComment != nil {
+		tpl = appendBody(updatedComment.Body, tpl, opts.AppendDelimiter)
+		fitBody = appendedBodyTooLong
+	}
 
 	cmtCtrl := CommentController{
 		GitHub:   ctrl.GitHub,
@@ -252,43 +582,102 @@ func (ctrl *PostController) getCommentParams(ctx context.Context, opts *option.P
 		Getenv:   ctrl.Getenv,
 		Platform: ctrl.Platform,
 	}
-	embeddedMetadata := make(map[string]interface{}, len(opts.EmbeddedVarNames))
-	for _, name := range opts.EmbeddedVarNames {
-		if v, ok := cfg.Vars[name]; ok {
-			embeddedMetadata[name] = v
+	embeddedComment := ""
+	if opts.NoMetadata {
+		if opts.UpdateCondition != "" {
+			logrus.WithFields(logrus.Fields{
+				"update_condition": opts.UpdateCondition,
+			}).Warn("no-metadata is set, so the posted comment can't be updated or hidden by the condition afterward")
+		}
+	} else {
+		embeddedVarNames := excludeNoEmbed(opts.EmbeddedVarNames, cfg.NoEmbed)
+		embeddedMetadata := make(map[string]interface{}, len(embeddedVarNames))
+		for _, name := range embeddedVarNames {
+			if v, ok := cfg.Vars[name]; ok {
+				embeddedMetadata[name] = v
+			}
+		}
+		if err := validateMetadataVars(embeddedMetadata, cfg.MetadataSchema); err != nil {
+			return nil, err
+		}
+		data := map[string]interface{}{
+			"SHA1":        opts.SHA1,
+			"TemplateKey": opts.TemplateKey,
+			"Vars":        embeddedMetadata,
+		}
+		if opts.Target != "" {
+			data["Target"] = opts.Target
 		}
+		if opts.Sticky != "" {
+			data["Sticky"] = opts.Sticky
+		}
+		if opts.IdempotencyKey != "" {
+			data["IdempotencyKey"] = opts.IdempotencyKey
+		}
+		extra, err := renderExtraMetadata(ctrl.Renderer, opts.ExtraMetadata, templates, tplParams)
+		if err != nil {
+			return nil, err
+		}
+		cmtCtrl.ExtraMetadata = extra
+		a, err := cmtCtrl.getEmbeddedComment(data)
+		if err != nil {
+			return nil, err
+		}
+		embeddedComment = a
 	}
-	embeddedComment, err := cmtCtrl.getEmbeddedComment(map[string]interface{}{
-		"SHA1":        opts.SHA1,
-		"TemplateKey": opts.TemplateKey,
-		"Vars":        embeddedMetadata,
-	})
+
+	footers, err := renderFooters(ctrl.Renderer, cfg.Footers, templates, tplParams)
 	if err != nil {
 		return nil, err
 	}
 
-	tpl += embeddedComment
-	tplForTooLong += embeddedComment
-
-	cmt := &github.Comment{
-		PRNumber:       opts.PRNumber,
-		Org:            opts.Org,
-		Repo:           opts.Repo,
-		Body:           tpl,
-		BodyForTooLong: tplForTooLong,
-		SHA1:           opts.SHA1,
-		HideOldComment: opts.HideOldComment,
-		Vars:           cfg.Vars,
-		TemplateKey:    opts.TemplateKey,
-	}
-	if opts.UpdateCondition != "" && opts.PRNumber != 0 {
-		if err := ctrl.setUpdatedCommentID(ctx, cmt, opts.UpdateCondition); err != nil {
+	debug := ""
+	if opts.DebugFooter {
+		a, err := renderDebugFooter(&debugInfo{
+			TemplateKey: opts.TemplateKey,
+			Config:      postDebugConfig(opts),
+			Vars:        cfg.Vars,
+			RunID:       getRunID(ctrl.Getenv),
+		})
+		if err != nil {
 			return nil, err
 		}
+		debug = a
+	}
+
+	cmt := &github.Comment{
+		PRNumber:         opts.PRNumber,
+		Org:              opts.Org,
+		Repo:             opts.Repo,
+		Body:             tpl,
+		BodyForTooLong:   tplForTooLong,
+		Metadata:         embeddedComment,
+		Debug:            debug,
+		MinimizeOnCreate: opts.MinimizeOnCreate,
+		SHA1:             opts.SHA1,
+		HideOldComment:   opts.HideOldComment,
+		Vars:             cfg.Vars,
+		TemplateKey:      opts.TemplateKey,
+		Footers:          footers,
+		FitBody:          fitBody,
+	}
+	if updatedComment != nil {
+		setUpdatedCommentID(cmt, updatedComment, opts)
+	}
+	if opts.CommentID != 0 {
+		cmt.CommentID = opts.CommentID
 	}
 	return cmt, nil
 }
 
+func postDebugConfig(opts *option.PostOptions) map[string]interface{} {
+	return map[string]interface{}{
+		"Command":         "post",
+		"UpdateCondition": opts.UpdateCondition,
+		"HideOldComment":  opts.HideOldComment,
+	}
+}
+
 func (ctrl *PostController) readTemplateFromStdin() (string, error) {
 	if !ctrl.HasStdin() {
 		return "", nil
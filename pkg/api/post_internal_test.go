@@ -2,11 +2,14 @@ package api
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 	"github.com/suzuki-shunsuke/github-comment/pkg/github"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
 	"github.com/suzuki-shunsuke/github-comment/pkg/template"
@@ -199,6 +202,7 @@ func TestPostController_getCommentParams(t *testing.T) { //nolint:funlen
 			require.Nil(t, err)
 			cmt.Body = ""
 			cmt.BodyForTooLong = ""
+			cmt.Metadata = ""
 			require.Equal(t, d.exp, cmt)
 		})
 	}
@@ -245,3 +249,429 @@ func TestPostController_readTemplateFromStdin(t *testing.T) {
 		})
 	}
 }
+
+func Test_isCreatedWithin(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	data := []struct {
+		title     string
+		createdAt string
+		exp       bool
+	}{
+		{
+			title:     "new comment",
+			createdAt: "2023-01-09T12:00:00Z",
+			exp:       true,
+		},
+		{
+			title:     "old comment",
+			createdAt: "2023-01-01T00:00:00Z",
+		},
+		{
+			title:     "invalid createdAt",
+			createdAt: "foo",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, isCreatedWithin(&github.IssueComment{
+				CreatedAt: d.createdAt,
+			}, 24*time.Hour, now))
+		})
+	}
+}
+
+func Test_isUpdatedWithin(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	data := []struct {
+		title   string
+		comment *github.IssueComment
+		exp     bool
+	}{
+		{
+			title: "recently updated comment",
+			comment: &github.IssueComment{
+				CreatedAt: "2023-01-01T00:00:00Z",
+				UpdatedAt: "2023-01-09T23:59:30Z",
+			},
+			exp: true,
+		},
+		{
+			title: "old comment",
+			comment: &github.IssueComment{
+				CreatedAt: "2023-01-01T00:00:00Z",
+				UpdatedAt: "2023-01-09T00:00:00Z",
+			},
+		},
+		{
+			title: "updatedAt is unknown",
+			comment: &github.IssueComment{
+				CreatedAt: "2023-01-09T23:59:30Z",
+			},
+			exp: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, isUpdatedWithin(d.comment, time.Minute, now))
+		})
+	}
+}
+
+func BenchmarkFindUpdatedComment(b *testing.B) {
+	prg, err := (&expr.Expr{}).Compile(`Comment.HasMeta && Comment.Meta.TemplateKey == "default" && Commit.SHA1 != ""`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cmt := &github.Comment{
+		Org:      "suzuki-shunsuke",
+		Repo:     "github-comment",
+		PRNumber: 1,
+		SHA1:     "0000000000000000000000000000000000000000",
+		Vars:     map[string]interface{}{},
+	}
+	for _, n := range []int{10, 100, 1000} { //nolint:gomnd
+		comments := make([]*github.IssueComment, n)
+		for i := range comments {
+			comments[i] = &github.IssueComment{
+				ID:         strconv.Itoa(i),
+				DatabaseID: int64(i),
+				Body:       `hello <!-- github-comment: {"SHA1":"0000000000000000000000000000000000000000","TemplateKey":"default"} -->`,
+			}
+		}
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			param := &ParamFindUpdatedComment{}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				FindUpdatedComment(prg, cmt, comments, param)
+			}
+		})
+	}
+}
+
+func TestPostController_getCommentParams_updateKey(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "plan\n<!-- github-comment: {\"Sticky\":\"shared\",\"TemplateKey\":\"plan\"} -->",
+		},
+		{
+			DatabaseID: 2,
+			Body:       "apply\n<!-- github-comment: {\"Sticky\":\"shared\",\"TemplateKey\":\"apply\"} -->",
+		},
+	}
+	data := []struct {
+		title        string
+		templateKey  string
+		sticky       string
+		expCommentID int64
+	}{
+		{
+			title:        "the comment with the same update key and template key is updated",
+			templateKey:  "plan",
+			expCommentID: 1,
+		},
+		{
+			title:        "the comment of the other template key isn't updated",
+			templateKey:  "apply",
+			expCommentID: 2,
+		},
+		{
+			title:       "a new comment is created for a new template key",
+			templateKey: "test",
+		},
+		{
+			title:        "sticky matches regardless of the template key",
+			templateKey:  "test",
+			sticky:       "shared",
+			expCommentID: 2,
+		},
+	}
+	ctx := context.Background()
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &PostController{
+				HasStdin: func() bool {
+					return false
+				},
+				Getenv: func(k string) string {
+					return ""
+				},
+				GitHub:   &fakeGitHub{comments: comments},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config: &config.Config{
+					UpdateKey: "shared",
+					Post: map[string]*config.PostConfig{
+						"plan":  {Template: "plan"},
+						"apply": {Template: "apply"},
+						"test":  {Template: "test"},
+					},
+				},
+			}
+			cmt, err := ctrl.getCommentParams(ctx, &option.PostOptions{
+				Options: option.Options{
+					Org:         "suzuki-shunsuke",
+					Repo:        "github-comment",
+					Token:       "xxx",
+					PRNumber:    1,
+					TemplateKey: d.templateKey,
+				},
+				Sticky: d.sticky,
+			})
+			require.Nil(t, err)
+			require.Equal(t, d.expCommentID, cmt.CommentID)
+		})
+	}
+}
+
+func TestPostController_getCommentParams_commentIf(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		commentIf string
+		isNil     bool
+	}{
+		{
+			title:     "comment(target) returns the metadata",
+			commentIf: `comment("plan").Vars.result == "success"`,
+		},
+		{
+			title:     "skip the comment",
+			commentIf: `comment("plan").Vars.result == "failure"`,
+			isNil:     true,
+		},
+	}
+	ctx := context.Background()
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &PostController{
+				HasStdin: func() bool {
+					return false
+				},
+				Getenv: func(k string) string {
+					return ""
+				},
+				GitHub: &fakeGitHub{
+					comments: []*github.IssueComment{
+						{
+							Body: "plan\n<!-- github-comment: {\"Target\":\"plan\",\"Vars\":{\"result\":\"success\"}} -->",
+						},
+					},
+				},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			cmt, err := ctrl.getCommentParams(ctx, &option.PostOptions{
+				Options: option.Options{
+					Org:      "suzuki-shunsuke",
+					Repo:     "github-comment",
+					Token:    "xxx",
+					PRNumber: 1,
+					Template: "apply",
+				},
+				CommentIf: d.commentIf,
+			})
+			require.Nil(t, err)
+			if d.isNil {
+				require.Nil(t, cmt)
+				return
+			}
+			require.NotNil(t, cmt)
+		})
+	}
+}
+
+func TestPostController_getCommentParams_commentID(t *testing.T) {
+	t.Parallel()
+	gh := &fakeGitHub{
+		comments: []*github.IssueComment{
+			{
+				DatabaseID: 1,
+				Body:       "plan\n<!-- github-comment: {\"IdempotencyKey\":\"foo\",\"TemplateKey\":\"plan\"} -->",
+			},
+		},
+	}
+	ctrl := &PostController{
+		HasStdin: func() bool {
+			return false
+		},
+		Getenv: func(k string) string {
+			return ""
+		},
+		GitHub:   gh,
+		Expr:     &expr.Expr{},
+		Renderer: &template.Renderer{},
+		Config: &config.Config{
+			Post: map[string]*config.PostConfig{
+				"plan": {Template: "plan"},
+			},
+		},
+	}
+	cmt, err := ctrl.getCommentParams(context.Background(), &option.PostOptions{
+		Options: option.Options{
+			Org:            "suzuki-shunsuke",
+			Repo:           "github-comment",
+			Token:          "xxx",
+			PRNumber:       1,
+			TemplateKey:    "plan",
+			IdempotencyKey: "foo",
+		},
+		UpdateCondition: "true",
+		CommentID:       2,
+	})
+	require.Nil(t, err)
+	require.NotNil(t, cmt)
+	require.Equal(t, int64(2), cmt.CommentID)
+	require.Nil(t, checkCommentLimit(context.Background(), gh, cmt, 1, nil))
+	require.Equal(t, 0, gh.listCalls)
+}
+
+func TestPostController_getCommentParams_commentExists(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "plan\n<!-- github-comment: {\"TemplateKey\":\"plan\"} -->",
+		},
+	}
+	data := []struct {
+		title           string
+		updateCondition string
+		exp             string
+	}{
+		{
+			title:           "a comment exists",
+			updateCondition: `Comment.Meta.TemplateKey == "plan"`,
+			exp:             "true",
+		},
+		{
+			title:           "no comment matches",
+			updateCondition: `Comment.Meta.TemplateKey == "apply"`,
+			exp:             "false",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &PostController{
+				HasStdin: func() bool {
+					return false
+				},
+				Getenv: func(k string) string {
+					return ""
+				},
+				GitHub:   &fakeGitHub{comments: comments},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			cmt, err := ctrl.getCommentParams(context.Background(), &option.PostOptions{
+				Options: option.Options{
+					Org:         "suzuki-shunsuke",
+					Repo:        "github-comment",
+					Token:       "xxx",
+					PRNumber:    1,
+					TemplateKey: "plan",
+					Template:    "{{.CommentExists}}",
+					NoMetadata:  true,
+				},
+				UpdateCondition: d.updateCondition,
+			})
+			require.Nil(t, err)
+			require.Equal(t, d.exp, cmt.Body)
+		})
+	}
+}
+
+func TestPostController_getCommentParams_once(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "plan\n<!-- github-comment: {\"TemplateKey\":\"plan\"} -->",
+		},
+	}
+	data := []struct {
+		title           string
+		updateCondition string
+		commentID       int64
+		isNil           bool
+		isErr           bool
+	}{
+		{
+			title:           "skip posting a comment because the comment exists",
+			updateCondition: `Comment.Meta.TemplateKey == "plan"`,
+			isNil:           true,
+		},
+		{
+			title:           "create a comment because no comment matches",
+			updateCondition: `Comment.Meta.TemplateKey == "apply"`,
+		},
+		{
+			title: "once requires an update condition",
+			isErr: true,
+		},
+		{
+			title:           "once can't be used with comment-id",
+			updateCondition: `Comment.Meta.TemplateKey == "apply"`,
+			commentID:       1,
+			isErr:           true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &PostController{
+				HasStdin: func() bool {
+					return false
+				},
+				Getenv: func(k string) string {
+					return ""
+				},
+				GitHub:   &fakeGitHub{comments: comments},
+				Expr:     &expr.Expr{},
+				Renderer: &template.Renderer{},
+				Config:   &config.Config{},
+			}
+			cmt, err := ctrl.getCommentParams(context.Background(), &option.PostOptions{
+				Options: option.Options{
+					Org:         "suzuki-shunsuke",
+					Repo:        "github-comment",
+					Token:       "xxx",
+					PRNumber:    1,
+					TemplateKey: "plan",
+					Template:    "hello",
+				},
+				UpdateCondition: d.updateCondition,
+				CommentID:       d.commentID,
+				Once:            true,
+			})
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			if d.isNil {
+				require.Nil(t, cmt)
+				return
+			}
+			require.NotNil(t, cmt)
+			require.Equal(t, int64(0), cmt.CommentID)
+		})
+	}
+}
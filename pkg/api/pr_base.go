@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/sirupsen/logrus"
+)
+
+// matchPRBase returns true if the base branch matches with any of the glob patterns.
+func matchPRBase(patterns []string, base string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, base)
+		if err != nil {
+			return false, fmt.Errorf("parse a glob pattern of pr-base %s: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkPRBase returns true if the base branch of the pull request matches with patterns.
+// If patterns are empty, true is returned.
+// If the pull request isn't found, false is returned because the base branch is unknown.
+func checkPRBase(ctx context.Context, gh GitHub, org, repo string, prNumber int, patterns []string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	logE := logrus.WithFields(logrus.Fields{
+		"pr_base": patterns,
+	})
+	if prNumber == 0 {
+		logE.Info("skip posting a comment because no pull request is found")
+		return false, nil
+	}
+	info, err := gh.PRInfo(ctx, org, repo, prNumber)
+	if err != nil {
+		return false, fmt.Errorf("get a pull request to check the base branch: %w", err)
+	}
+	matched, err := matchPRBase(patterns, info.BaseRef)
+	if err != nil {
+		return false, err
+	}
+	if !matched {
+		logE.WithField("base", info.BaseRef).Info("skip posting a comment because the base branch of the pull request doesn't match with pr-base")
+	}
+	return matched, nil
+}
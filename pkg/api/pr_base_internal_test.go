@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_matchPRBase(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		patterns []string
+		base     string
+		exp      bool
+		isErr    bool
+	}{
+		{
+			title:    "exact match",
+			patterns: []string{"main", "release/*"},
+			base:     "main",
+			exp:      true,
+		},
+		{
+			title:    "glob",
+			patterns: []string{"main", "release/*"},
+			base:     "release/v1",
+			exp:      true,
+		},
+		{
+			title:    "* doesn't match /",
+			patterns: []string{"release/*"},
+			base:     "release/v1/hotfix",
+		},
+		{
+			title:    "not matched",
+			patterns: []string{"main"},
+			base:     "develop",
+		},
+		{
+			title:    "invalid pattern",
+			patterns: []string{"[main"},
+			base:     "main",
+			isErr:    true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			matched, err := matchPRBase(d.patterns, d.base)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, matched)
+		})
+	}
+}
+
+func Test_checkPRBase(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title          string
+		patterns       []string
+		prNumber       int
+		base           string
+		exp            bool
+		expPRInfoCalls int
+		isErr          bool
+	}{
+		{
+			title:    "no pattern",
+			prNumber: 1,
+			exp:      true,
+		},
+		{
+			title:    "no pull request",
+			patterns: []string{"main"},
+		},
+		{
+			title:          "matched",
+			patterns:       []string{"main"},
+			prNumber:       1,
+			base:           "main",
+			exp:            true,
+			expPRInfoCalls: 1,
+		},
+		{
+			title:          "not matched",
+			patterns:       []string{"main"},
+			prNumber:       1,
+			base:           "develop",
+			expPRInfoCalls: 1,
+		},
+		{
+			title:          "invalid pattern",
+			patterns:       []string{"[main"},
+			prNumber:       1,
+			base:           "main",
+			expPRInfoCalls: 1,
+			isErr:          true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{
+				prInfo: &github.PRInfo{
+					BaseRef: d.base,
+				},
+			}
+			matched, err := checkPRBase(context.Background(), gh, "suzuki-shunsuke", "github-comment", d.prNumber, d.patterns)
+			require.Equal(t, d.expPRInfoCalls, gh.prInfoCalls)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, matched)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+// ExecCommit is the size of the pull request.
+// It can be referred in exec's when conditions as Commit.Additions, Commit.Deletions, and Commit.ChangedFiles.
+type ExecCommit struct {
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+}
+
+// needsPRInfo returns true if any when condition refers the size of the pull request.
+// The pull request is fetched only if it's needed because it requires an API call.
+func needsPRInfo(execConfigs []*config.ExecConfig) bool {
+	for _, execConfig := range execConfigs {
+		for _, field := range []string{"Commit.Additions", "Commit.Deletions", "Commit.ChangedFiles"} {
+			if strings.Contains(execConfig.When, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setPRInfo fetches the size of the pull request and sets it to cmtParams.
+// If it fails to fetch the pull request, the size is kept as zero and a warning is output.
+func (ctrl *ExecController) setPRInfo(ctx context.Context, execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams) {
+	if cmtParams.PRNumber == 0 || !needsPRInfo(execConfigs) {
+		return
+	}
+	info, err := ctrl.GitHub.PRInfo(ctx, cmtParams.Org, cmtParams.Repo, cmtParams.PRNumber)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"org":       cmtParams.Org,
+			"repo":      cmtParams.Repo,
+			"pr_number": cmtParams.PRNumber,
+		}).Warn("get the size of the pull request")
+		return
+	}
+	cmtParams.Commit = ExecCommit{
+		Additions:    info.Additions,
+		Deletions:    info.Deletions,
+		ChangedFiles: info.ChangedFiles,
+	}
+}
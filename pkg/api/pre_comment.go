@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/execute"
+)
+
+// runPreCommentCommand runs the command with `sh -c` just before the comment is posted.
+// The rendered comment body is passed via the standard input.
+// The standard output must be a JSON object, which is merged into the template variables.
+// The output isn't written to github-comment's standard output so that it doesn't mix with the output of the command.
+func (ctrl *ExecController) runPreCommentCommand(ctx context.Context, command, body string) (map[string]interface{}, error) {
+	result, err := ctrl.PreCommentExecutor.Run(ctx, &execute.Params{
+		Cmd:   "sh",
+		Args:  []string{"-c", command},
+		Stdin: strings.NewReader(body),
+	})
+	if err != nil {
+		if result != nil && result.Stderr != "" {
+			return nil, fmt.Errorf("run pre_comment_command: %w: %s", err, result.Stderr)
+		}
+		return nil, fmt.Errorf("run pre_comment_command: %w", err)
+	}
+	vars := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(result.Stdout), &vars); err != nil {
+		return nil, fmt.Errorf("parse the standard output of pre_comment_command as a JSON object: %w", err)
+	}
+	return vars, nil
+}
+
+// applyPreCommentCommand runs pre_comment_command and re-renders templates with variables which the command outputs.
+// If the command fails, a warning is output and the original parameters and bodies are returned.
+// In dry-run, the command isn't run because it may have side effects such as uploading artifacts.
+func (ctrl *ExecController) applyPreCommentCommand(
+	ctx context.Context, command, tpl, tplForTooLong string, templates map[string]string,
+	cmtParams *ExecCommentParams, body, bodyForTooLong string,
+) (*ExecCommentParams, string, string) {
+	logE := logrus.WithFields(logrus.Fields{
+		"pre_comment_command": command,
+	})
+	if cmtParams.DryRun {
+		logE.Info("skip pre_comment_command in dry-run")
+		return cmtParams, body, bodyForTooLong
+	}
+	vars, err := ctrl.runPreCommentCommand(ctx, command, body)
+	if err != nil {
+		logE.WithError(err).Warn("post the comment without the result of pre_comment_command")
+		return cmtParams, body, bodyForTooLong
+	}
+	params := *cmtParams
+	params.Vars = make(map[string]interface{}, len(cmtParams.Vars)+len(vars))
+	for k, v := range cmtParams.Vars {
+		params.Vars[k] = v
+	}
+	for k, v := range vars {
+		params.Vars[k] = v
+	}
+	newBody, err := ctrl.Renderer.Render(tpl, templates, &params)
+	if err != nil {
+		logE.WithError(err).Warn("re-render a comment template with the result of pre_comment_command")
+		return cmtParams, body, bodyForTooLong
+	}
+	newBodyForTooLong, err := ctrl.Renderer.Render(tplForTooLong, templates, &params)
+	if err != nil {
+		logE.WithError(err).Warn("re-render a comment template_for_too_long with the result of pre_comment_command")
+		return cmtParams, body, bodyForTooLong
+	}
+	return &params, newBody, newBodyForTooLong
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/execute"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+type preCommentExecutor struct {
+	stdout string
+	err    error
+	body   string
+	runs   int
+}
+
+func (executor *preCommentExecutor) Run(ctx context.Context, params *execute.Params) (*execute.Result, error) {
+	executor.runs++
+	b, err := io.ReadAll(params.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	executor.body = string(b)
+	return &execute.Result{
+		Stdout: executor.stdout,
+	}, executor.err
+}
+
+func TestExecController_applyPreCommentCommand(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		executor *preCommentExecutor
+		dryRun   bool
+		exp      string
+		expRuns  int
+	}{
+		{
+			title:    "the output is merged into vars",
+			executor: &preCommentExecutor{stdout: `{"url": "https://example.com"}`},
+			exp:      "foo https://example.com",
+			expRuns:  1,
+		},
+		{
+			title:    "the original body is posted if the command fails",
+			executor: &preCommentExecutor{err: errors.New("exit status 1")},
+			exp:      "original",
+			expRuns:  1,
+		},
+		{
+			title:    "the original body is posted if the output isn't a JSON object",
+			executor: &preCommentExecutor{stdout: "https://example.com"},
+			exp:      "original",
+			expRuns:  1,
+		},
+		{
+			title:    "the command isn't run in dry-run",
+			executor: &preCommentExecutor{stdout: `{"url": "https://example.com"}`},
+			dryRun:   true,
+			exp:      "original",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &ExecController{
+				Executor:           &countExecutor{},
+				PreCommentExecutor: d.executor,
+				Renderer:           &template.Renderer{},
+			}
+			params := &ExecCommentParams{
+				Vars:   map[string]interface{}{"name": "foo"},
+				DryRun: d.dryRun,
+			}
+			_, body, _ := ctrl.applyPreCommentCommand(
+				context.Background(), "upload", "{{.Vars.name}} {{.Vars.url}}", "", nil, params, "original", "")
+			require.Equal(t, d.exp, body)
+			require.Equal(t, d.expRuns, d.executor.runs)
+			if d.expRuns != 0 {
+				require.Equal(t, "original", d.executor.body)
+			}
+			require.Equal(t, 0, ctrl.Executor.(*countExecutor).runs)
+		})
+	}
+}
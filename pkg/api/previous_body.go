@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	metadataPattern    = regexp.MustCompile(`(?s)<!-- github-comment: .*? -->`)                                                     //nolint:gochecknoglobals
+	debugFooterPattern = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(debugFooterStart) + `.*?` + regexp.QuoteMeta(debugFooterEnd)) //nolint:gochecknoglobals
+	footerPattern      = regexp.MustCompile(`(?s)\n?` + regexp.QuoteMeta(footerStart) + `.*?` + regexp.QuoteMeta(footerEnd))        //nolint:gochecknoglobals
+)
+
+// appendBody appends body to the body of the existing comment with delimiter.
+// The embedded metadata, the footers, and the debug footer of the existing comment are removed because new ones are appended.
+// Appending races between simultaneous jobs. See option.PostOptions.Append.
+func appendBody(existingBody, body, delimiter string) string {
+	existingBody = stripMetadata(existingBody)
+	if existingBody == "" {
+		return body
+	}
+	return existingBody + delimiter + body
+}
+
+// stripMetadata removes the embedded metadata, the footers, and the debug footer from the comment body.
+func stripMetadata(body string) string {
+	body = metadataPattern.ReplaceAllString(body, "")
+	body = debugFooterPattern.ReplaceAllString(body, "")
+	body = footerPattern.ReplaceAllString(body, "")
+	return strings.TrimRight(body, "\n")
+}
+
+// appendedBodyTooLong is FitBody of the appended comment.
+// If the appended comment exceeds the max length, posting fails
+// instead of falling back to template_for_too_long, which would drop the bodies appended by other jobs.
+func appendedBodyTooLong(limit int) (string, error) {
+	return "", fmt.Errorf("the appended comment exceeds the max length %d, so the comment isn't updated. Post a new comment instead of appending to it", limit)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_appendBody(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		existing string
+		body     string
+		exp      string
+	}{
+		{
+			title:    "the metadata of the existing comment is removed",
+			existing: "| job | result |\n|---|---|\n| a | ok |\n<!-- github-comment: {\"TemplateKey\":\"default\"} -->",
+			body:     "| b | ok |",
+			exp:      "| job | result |\n|---|---|\n| a | ok |\n| b | ok |",
+		},
+		{
+			title:    "the footers of the existing comment are removed",
+			existing: "| job | result |\n|---|---|\n| a | ok |\n<!-- github-comment-footer-start -->\nfooter\n<!-- github-comment-footer-end -->" + "<!-- github-comment: {\"TemplateKey\":\"default\"} -->",
+			body:     "| b | ok |",
+			exp:      "| job | result |\n|---|---|\n| a | ok |\n| b | ok |",
+		},
+		{
+			title:    "the existing comment has only metadata",
+			existing: "<!-- github-comment: {\"TemplateKey\":\"default\"} -->",
+			body:     "hello",
+			exp:      "hello",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, appendBody(d.existing, d.body, "\n"))
+		})
+	}
+}
+
+func TestCommentController_Post_appendedBodyTooLong(t *testing.T) {
+	t.Parallel()
+	gh := &fakeGitHub{}
+	ctrl := &CommentController{
+		GitHub: gh,
+		Expr:   &expr.Expr{},
+	}
+	err := ctrl.Post(context.Background(), &github.Comment{
+		Org:            "suzuki-shunsuke",
+		Repo:           "github-comment",
+		PRNumber:       1,
+		Body:           strings.Repeat("| a | ok |\n", github.MaxCommentLength/10),
+		BodyForTooLong: "too long",
+		FitBody:        appendedBodyTooLong,
+	}, nil)
+	require.NotNil(t, err)
+	require.Nil(t, gh.createdComment)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+type PruneController struct {
+	// Wd is a path to the working directory
+	Wd string
+	// Getenv returns the environment variable. os.Getenv
+	Getenv   func(string) string
+	Stderr   io.Writer
+	GitHub   GitHub
+	Platform Platform
+	Config   *config.Config
+}
+
+// Prune deletes comments posted by github-comment.
+// If opts.DryRun is true, comments aren't deleted and they are output to the standard error output.
+func (ctrl *PruneController) Prune(ctx context.Context, opts *option.PruneOptions) error { //nolint:cyclop
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "github-comment",
+	})
+	if ctrl.Platform != nil {
+		if err := ctrl.Platform.ComplementPrune(opts); err != nil {
+			return fmt.Errorf("failed to complement opts with platform built in environment variables: %w", err)
+		}
+	}
+
+	cfg := ctrl.Config
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if err := option.ValidatePrune(opts); err != nil {
+		return fmt.Errorf("opts is invalid: %w", err)
+	}
+
+	login, err := ctrl.getPruneAuthor(ctx, opts.Author)
+	if err != nil {
+		return err
+	}
+
+	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+		Org:      opts.Org,
+		Repo:     opts.Repo,
+		PRNumber: opts.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list issue or pull request comments: %w", err)
+	}
+
+	for _, comment := range comments {
+		if !isPrunedComment(comment, login, opts.TemplateKey, cfg.MetadataSchema) {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"comment_id": comment.DatabaseID,
+		})
+		if opts.DryRun {
+			if !opts.Silent {
+				fmt.Fprintln(ctrl.Stderr, "[github-comment][DRYRUN] Delete a comment "+opts.Org+"/"+opts.Repo+" comment_id:"+strconv.FormatInt(comment.DatabaseID, 10))
+			}
+			continue
+		}
+		if err := ctrl.GitHub.DeleteComment(ctx, opts.Org, opts.Repo, comment.DatabaseID); err != nil {
+			logE.WithError(err).Error("delete a comment")
+			continue
+		}
+		logE.Info("delete a comment")
+	}
+	return nil
+}
+
+// getPruneAuthor returns the login of the user who posted comments to be pruned.
+// Unlike hide, deleted comments can't be restored,
+// so prune doesn't run if the author is unknown instead of giving up filtering comments by the author.
+func (ctrl *PruneController) getPruneAuthor(ctx context.Context, author string) (string, error) {
+	if author != "" {
+		return author, nil
+	}
+	login, err := ctrl.GitHub.GetAuthenticatedUser(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("get an authenticated user")
+	}
+	if login == "" {
+		return "", errors.New("the author of comments to be pruned is unknown because the authenticated user can't be gotten. Please set --author")
+	}
+	return login, nil
+}
+
+// isPrunedComment returns true if the comment was posted by github-comment.
+// If templateKey isn't empty, only comments whose TemplateKey is templateKey are pruned.
+func isPrunedComment(comment *github.IssueComment, login, templateKey string, schema map[string]string) bool {
+	// GitHub Actions's GITHUB_TOKEN secret doesn't have a permission to get an authenticated user.
+	// So if `login` is empty, we give up filtering comments by login.
+	if login != "" && comment.Author.Login != login {
+		return false
+	}
+	metadata := map[string]interface{}{}
+	if !extractMetaFromComment(comment.Body, &metadata, schema) {
+		return false
+	}
+	if templateKey == "" {
+		return true
+	}
+	key, ok := metadata["TemplateKey"].(string)
+	return ok && key == templateKey
+}
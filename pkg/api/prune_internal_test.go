@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func TestPruneController_Prune(t *testing.T) {
+	t.Parallel()
+	newComments := func() []*github.IssueComment {
+		a := newFakeComment("octocat", "<!-- github-comment: {\"TemplateKey\":\"plan\"} -->", false)
+		a.DatabaseID = 1
+		b := newFakeComment("foo", "<!-- github-comment: {\"TemplateKey\":\"plan\"} -->", false)
+		b.DatabaseID = 2
+		c := newFakeComment("octocat", "lgtm", false)
+		c.DatabaseID = 3
+		return []*github.IssueComment{a, b, c}
+	}
+	data := []struct {
+		title  string
+		login  string
+		author string
+		exp    []int64
+		isErr  bool
+	}{
+		{
+			title: "the authenticated user",
+			login: "octocat",
+			exp:   []int64{1},
+		},
+		{
+			title:  "author",
+			author: "foo",
+			exp:    []int64{2},
+		},
+		{
+			title: "the author is unknown",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{
+				login:    d.login,
+				comments: newComments(),
+			}
+			ctrl := &PruneController{
+				GitHub: gh,
+				Config: &config.Config{},
+			}
+			err := ctrl.Prune(context.Background(), &option.PruneOptions{
+				Options: option.Options{
+					Org:      "suzuki-shunsuke",
+					Repo:     "github-comment",
+					Token:    "xxx",
+					PRNumber: 1,
+				},
+				Author: d.author,
+			})
+			if d.isErr {
+				require.NotNil(t, err)
+				require.Nil(t, gh.deletedComments)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, gh.deletedComments)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+type ReactController struct {
+	Stderr   io.Writer
+	GitHub   GitHub
+	Platform Platform
+	Config   *config.Config
+	Expr     Expr
+}
+
+// React adds a reaction to the latest comment which matches with the update condition.
+// If opts.UpdateCondition is empty, the update condition of the post config of the template key is used.
+// If it's also empty, the default update condition of the config is used.
+// If it's also empty, the latest comment with the template key is matched.
+// If opts.DryRun is true, the reaction isn't added and it's output to the standard error output.
+func (ctrl *ReactController) React(ctx context.Context, opts *option.ReactOptions) error {
+	if ctrl.Platform != nil {
+		if err := ctrl.Platform.ComplementReact(opts); err != nil {
+			return fmt.Errorf("failed to complement opts with platform built in environment variables: %w", err)
+		}
+	}
+
+	cfg := ctrl.Config
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if err := option.ValidateReact(opts); err != nil {
+		return fmt.Errorf("opts is invalid: %w", err)
+	}
+
+	if opts.UpdateCondition == "" {
+		if postConfig, ok := cfg.Post[opts.TemplateKey]; ok {
+			opts.UpdateCondition = postConfig.UpdateCondition
+		}
+	}
+	if opts.UpdateCondition == "" {
+		opts.UpdateCondition = cfg.DefaultUpdateCondition
+	}
+	if opts.UpdateCondition == "" {
+		opts.UpdateCondition = fmt.Sprintf("Comment.HasMeta && Comment.Meta.TemplateKey == %q", opts.TemplateKey)
+	}
+	prg, err := ctrl.Expr.Compile(opts.UpdateCondition)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	login, err := ctrl.GitHub.GetAuthenticatedUser(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("get an authenticated user")
+	}
+
+	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+		Org:      opts.Org,
+		Repo:     opts.Repo,
+		PRNumber: opts.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list issue or pull request comments: %w", err)
+	}
+
+	comment := FindUpdatedComment(prg, &github.Comment{
+		Org:         opts.Org,
+		Repo:        opts.Repo,
+		PRNumber:    opts.PRNumber,
+		SHA1:        opts.SHA1,
+		TemplateKey: opts.TemplateKey,
+		Vars:        cfg.Vars,
+	}, comments, &ParamFindUpdatedComment{
+		Login:     login,
+		Condition: opts.UpdateCondition,
+
+		AuthorAssociations: cfg.AuthorAssociations,
+		MetadataSchema:     cfg.MetadataSchema,
+	})
+	if comment == nil {
+		logrus.WithFields(logrus.Fields{
+			"update_condition": opts.UpdateCondition,
+		}).Info("no reaction is added because no comment matches with the condition")
+		return nil
+	}
+	if opts.DryRun {
+		if !opts.Silent {
+			fmt.Fprintln(ctrl.Stderr, "[github-comment][DRYRUN] Add a reaction "+opts.Content+" to the comment "+strconv.FormatInt(comment.DatabaseID, 10))
+		}
+		return nil
+	}
+	if err := ctrl.GitHub.AddReaction(ctx, opts.Org, opts.Repo, comment.DatabaseID, opts.Content); err != nil {
+		return fmt.Errorf("add a reaction: %w", err)
+	}
+	return nil
+}
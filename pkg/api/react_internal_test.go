@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func TestReactController_React(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			DatabaseID: 1,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"plan\"} -->",
+		},
+		{
+			DatabaseID: 2,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"plan\"} -->",
+		},
+		{
+			DatabaseID: 3,
+			Body:       "<!-- github-comment: {\"TemplateKey\":\"apply\"} -->",
+		},
+	}
+	data := []struct {
+		title     string
+		cfg       *config.Config
+		opts      *option.ReactOptions
+		exp       []string
+		expStderr string
+		isErr     bool
+	}{
+		{
+			title: "the latest comment with the template key",
+			cfg:   &config.Config{},
+			opts: &option.ReactOptions{
+				Options: option.Options{TemplateKey: "plan"},
+				Content: "rocket",
+			},
+			exp: []string{"2:rocket"},
+		},
+		{
+			title: "the update condition of the post config",
+			cfg: &config.Config{
+				Post: map[string]*config.PostConfig{
+					"plan": {UpdateCondition: `Comment.Meta.TemplateKey == "apply"`},
+				},
+			},
+			opts: &option.ReactOptions{
+				Options: option.Options{TemplateKey: "plan"},
+				Content: "+1",
+			},
+			exp: []string{"3:+1"},
+		},
+		{
+			title: "the command line option takes precedence",
+			cfg: &config.Config{
+				DefaultUpdateCondition: `Comment.Meta.TemplateKey == "apply"`,
+			},
+			opts: &option.ReactOptions{
+				Options:         option.Options{TemplateKey: "plan"},
+				Content:         "eyes",
+				UpdateCondition: `Comment.Meta.TemplateKey == "plan"`,
+			},
+			exp: []string{"2:eyes"},
+		},
+		{
+			title: "no comment matches",
+			cfg:   &config.Config{},
+			opts: &option.ReactOptions{
+				Options: option.Options{TemplateKey: "test"},
+				Content: "rocket",
+			},
+		},
+		{
+			title: "dry-run",
+			cfg:   &config.Config{},
+			opts: &option.ReactOptions{
+				Options: option.Options{TemplateKey: "plan", DryRun: true},
+				Content: "rocket",
+			},
+			expStderr: "[github-comment][DRYRUN] Add a reaction rocket to the comment 2\n",
+		},
+		{
+			title: "invalid content",
+			cfg:   &config.Config{},
+			opts: &option.ReactOptions{
+				Options: option.Options{TemplateKey: "plan"},
+				Content: "foo",
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			gh := &fakeGitHub{comments: comments}
+			stderr := &bytes.Buffer{}
+			ctrl := &ReactController{
+				Stderr: stderr,
+				GitHub: gh,
+				Config: d.cfg,
+				Expr:   &expr.Expr{},
+			}
+			d.opts.Org = "suzuki-shunsuke"
+			d.opts.Repo = "github-comment"
+			d.opts.PRNumber = 1
+			d.opts.SkipNoToken = true
+			err := ctrl.React(context.Background(), d.opts)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, gh.reactions)
+			require.Equal(t, d.expStderr, stderr.String())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const redactedText = "***"
+
+// redact replaces strings matching with any of patterns with "***".
+// This catches dynamically formatted secrets such as tokens and IP addresses.
+func redact(body string, patterns []string) (string, error) {
+	for _, pattern := range patterns {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", fmt.Errorf("compile redact_patterns %s: %w", pattern, err)
+		}
+		body = r.ReplaceAllLiteralString(body, redactedText)
+	}
+	return body, nil
+}
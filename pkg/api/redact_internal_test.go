@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_redact(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		body     string
+		patterns []string
+		exp      string
+		isErr    bool
+	}{
+		{
+			title: "no pattern",
+			body:  "token: ghp_xxx",
+			exp:   "token: ghp_xxx",
+		},
+		{
+			title:    "multiple patterns",
+			body:     "token: ghp_abc123 ip: 192.168.0.1",
+			patterns: []string{`ghp_[A-Za-z0-9]+`, `\d+\.\d+\.\d+\.\d+`},
+			exp:      "token: *** ip: ***",
+		},
+		{
+			title:    "invalid pattern",
+			body:     "hello",
+			patterns: []string{`(`},
+			isErr:    true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			body, err := redact(d.body, d.patterns)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, body)
+		})
+	}
+}
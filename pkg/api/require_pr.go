@@ -0,0 +1,19 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
+)
+
+// ExitCodeNoPR is the exit code when --require-pr is set but no pull request is found.
+const ExitCodeNoPR = 3
+
+// validateRequirePR returns an error if RequirePR is set but no pull request is resolved.
+func validateRequirePR(opts *option.Options) error {
+	if !opts.RequirePR || opts.PRNumber > 0 {
+		return nil
+	}
+	return ecerror.Wrap(errors.New("no pull request is found but require-pr is set"), ExitCodeNoPR)
+}
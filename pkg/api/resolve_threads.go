@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+type ResolveThreadsController struct {
+	// Wd is a path to the working directory
+	Wd string
+	// Getenv returns the environment variable. os.Getenv
+	Getenv   func(string) string
+	Stderr   io.Writer
+	GitHub   GitHub
+	Platform Platform
+	Config   *config.Config
+}
+
+// ResolveThreads minimizes review comments posted by github-comment whose threads are resolved.
+// If opts.ResolveOutdated is true, unresolved outdated threads started by github-comment are resolved and their comments are minimized too.
+// If opts.DryRun is true, threads and comments aren't changed and they are output to the standard error output.
+func (ctrl *ResolveThreadsController) ResolveThreads(ctx context.Context, opts *option.ResolveThreadsOptions) error { //nolint:cyclop
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "github-comment",
+	})
+	if ctrl.Platform != nil {
+		if err := ctrl.Platform.ComplementResolveThreads(opts); err != nil {
+			return fmt.Errorf("failed to complement opts with platform built in environment variables: %w", err)
+		}
+	}
+
+	cfg := ctrl.Config
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if err := option.ValidateResolveThreads(opts); err != nil {
+		return fmt.Errorf("opts is invalid: %w", err)
+	}
+
+	login, err := ctrl.GitHub.GetAuthenticatedUser(ctx)
+	if err != nil {
+		logE.WithError(err).Warn("get an authenticated user")
+	}
+
+	threads, err := ctrl.GitHub.ListReviewThreads(ctx, &github.PullRequest{
+		Org:      opts.Org,
+		Repo:     opts.Repo,
+		PRNumber: opts.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list review threads: %w", err)
+	}
+
+	for _, thread := range threads {
+		logE := logE.WithFields(logrus.Fields{
+			"thread_id": thread.ID,
+		})
+		comments := ownThreadComments(thread, login, opts.TemplateKey, cfg.MetadataSchema)
+		if len(comments) == 0 {
+			continue
+		}
+		if !thread.IsResolved {
+			if !opts.ResolveOutdated || !thread.IsOutdated || comments[0] != thread.Comments.Nodes[0] {
+				continue
+			}
+			if opts.DryRun {
+				if !opts.Silent {
+					fmt.Fprintln(ctrl.Stderr, "[github-comment][DRYRUN] Resolve a review thread "+thread.ID)
+				}
+			} else {
+				if err := ctrl.GitHub.ResolveReviewThread(ctx, thread.ID); err != nil {
+					logE.WithError(err).Error("resolve a review thread")
+					continue
+				}
+				logE.Info("resolve a review thread")
+			}
+		}
+		ctrl.minimizeThreadComments(ctx, logE, comments, opts)
+	}
+	return nil
+}
+
+func (ctrl *ResolveThreadsController) minimizeThreadComments(ctx context.Context, logE *logrus.Entry, comments []*github.IssueComment, opts *option.ResolveThreadsOptions) {
+	for _, comment := range comments {
+		if comment.IsMinimized {
+			continue
+		}
+		logE := logE.WithFields(logrus.Fields{
+			"node_id": comment.ID,
+		})
+		if opts.DryRun {
+			if !opts.Silent {
+				fmt.Fprintln(ctrl.Stderr, "[github-comment][DRYRUN] Minimize a review comment "+comment.ID)
+			}
+			continue
+		}
+		minimized, err := ctrl.GitHub.HideComment(ctx, comment.ID)
+		if err != nil {
+			logE.WithError(err).Error("minimize a review comment")
+			continue
+		}
+		if !minimized {
+			logE.Warn("GitHub didn't minimize a review comment. The token may not have the permission to minimize it")
+			continue
+		}
+		logE.Info("minimize a review comment")
+	}
+}
+
+// ownThreadComments returns comments of the thread which were posted by github-comment.
+func ownThreadComments(thread *github.ReviewThread, login, templateKey string, schema map[string]string) []*github.IssueComment {
+	var comments []*github.IssueComment
+	for _, comment := range thread.Comments.Nodes {
+		if isPrunedComment(comment, login, templateKey, schema) {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// renderReviewPosition renders the file path and the line number of the review comment.
+// If the rendered path is empty, an empty path is returned and the comment is posted as an issue comment.
+func (ctrl *ExecController) renderReviewPosition(pathTpl, lineTpl string, templates map[string]string, cmtParams *ExecCommentParams) (string, int, error) {
+	if pathTpl == "" {
+		return "", 0, nil
+	}
+	path, err := ctrl.Renderer.Render(pathTpl, templates, cmtParams)
+	if err != nil {
+		return "", 0, fmt.Errorf("render a review comment path: %w", err)
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", 0, nil
+	}
+	if lineTpl == "" {
+		return "", 0, errors.New("line is required if path is set")
+	}
+	s, err := ctrl.Renderer.Render(lineTpl, templates, cmtParams)
+	if err != nil {
+		return "", 0, fmt.Errorf("render a review comment line: %w", err)
+	}
+	line, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return "", 0, fmt.Errorf("parse a review comment line as an integer: %w", err)
+	}
+	if line <= 0 {
+		return "", 0, fmt.Errorf("a review comment line must be positive: %d", line)
+	}
+	return path, line, nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+func TestExecController_renderReviewPosition(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		path    string
+		line    string
+		expPath string
+		expLine int
+		isErr   bool
+	}{
+		{
+			title: "path isn't set",
+		},
+		{
+			title:   "path and line are rendered",
+			path:    `{{.Vars.file}}`,
+			line:    `{{.Vars.line}}`,
+			expPath: "main.go",
+			expLine: 10,
+		},
+		{
+			title: "rendered path is empty",
+			path:  `{{.Vars.none}}`,
+			line:  "10",
+		},
+		{
+			title: "line isn't set",
+			path:  "main.go",
+			isErr: true,
+		},
+		{
+			title: "line isn't a number",
+			path:  "main.go",
+			line:  "foo",
+			isErr: true,
+		},
+		{
+			title: "line isn't positive",
+			path:  "main.go",
+			line:  "0",
+			isErr: true,
+		},
+	}
+	ctrl := &ExecController{
+		Renderer: &template.Renderer{
+			Getenv: func(string) string {
+				return ""
+			},
+		},
+	}
+	params := &ExecCommentParams{
+		Vars: map[string]interface{}{
+			"file": "main.go",
+			"line": 10,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			path, line, err := ctrl.renderReviewPosition(d.path, d.line, nil, params)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.expPath, path)
+			require.Equal(t, d.expLine, line)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// sessionState is a state shared by exec invocations in the same session such as a CI job.
+type sessionState struct {
+	// FailureCommented is true if a comment about a failed command has already been posted in the session.
+	FailureCommented bool
+}
+
+// readSessionState reads the session state from the file.
+// If the file doesn't exist, the zero value is returned.
+func readSessionState(p string) (*sessionState, error) {
+	state := &sessionState{}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("read a session file %s: %w", p, err)
+	}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("parse a session file %s as JSON: %w", p, err)
+	}
+	return state, nil
+}
+
+func writeSessionState(p string, state *sessionState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal the session state as JSON: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0o600); err != nil { //nolint:gomnd
+		return fmt.Errorf("write a session file %s: %w", p, err)
+	}
+	return nil
+}
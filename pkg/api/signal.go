@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// signalPostTimeout is the timeout of posting the comment after the command is stopped by a signal.
+const signalPostTimeout = 30 * time.Second
+
+// detachedContext is a context which isn't cancelled when the parent is cancelled.
+// context.WithoutCancel is available only in Go 1.21 or later.
+type detachedContext struct {
+	context.Context //nolint:containedctx
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// withoutCancel returns a context which keeps values of ctx but isn't cancelled when ctx is cancelled.
+func withoutCancel(ctx context.Context) context.Context {
+	return detachedContext{Context: ctx}
+}
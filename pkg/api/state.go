@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// ExecState is a result of a command which is saved in the state file.
+type ExecState struct {
+	ExitCode       int
+	Command        string
+	JoinCommand    string
+	Stdout         string
+	Stderr         string
+	CombinedOutput string
+}
+
+// readExecStates reads results of previous runs from the state file.
+// If the file doesn't exist, nil is returned.
+func readExecStates(p string) ([]*ExecState, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read a state file %s: %w", p, err)
+	}
+	var states []*ExecState
+	if err := json.Unmarshal(b, &states); err != nil {
+		return nil, fmt.Errorf("parse a state file %s as JSON: %w", p, err)
+	}
+	return states, nil
+}
+
+func writeExecStates(p string, states []*ExecState) error {
+	b, err := json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("marshal states as JSON: %w", err)
+	}
+	if err := os.WriteFile(p, b, 0o600); err != nil { //nolint:gomnd
+		return fmt.Errorf("write a state file %s: %w", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+// setStatus sets a commit status according to the matched ExecConfig's status.
+// If no ExecConfig matches or the matched ExecConfig has no status, nothing is done.
+func (ctrl *ExecController) setStatus(
+	ctx context.Context, execConfigs []*config.ExecConfig, cmtParams *ExecCommentParams,
+	templates map[string]string,
+) error {
+	if cmtParams.Template != "" {
+		return nil
+	}
+	execConfig, f, err := ctrl.getExecConfig(execConfigs, cmtParams)
+	if err != nil {
+		return err
+	}
+	if !f || execConfig.Status == nil {
+		return nil
+	}
+	status := execConfig.Status
+	cmtParams, err = applyExitCodeFromOutput(execConfig, cmtParams)
+	if err != nil {
+		return err
+	}
+	if status.Context == "" {
+		return errors.New("status.context is required")
+	}
+	if cmtParams.SHA1 == "" {
+		logrus.WithFields(logrus.Fields{
+			"context": status.Context,
+		}).Warn("a commit status isn't set because sha1 is empty")
+		return nil
+	}
+	description, err := ctrl.Renderer.Render(status.Description, templates, cmtParams)
+	if err != nil {
+		return fmt.Errorf("render a status description: %w", err)
+	}
+	targetURL, err := ctrl.Renderer.Render(status.TargetURL, templates, cmtParams)
+	if err != nil {
+		return fmt.Errorf("render a status target_url: %w", err)
+	}
+	state, err := getStatusState(status.States, cmtParams.ExitCode)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.GitHub.CreateStatus(ctx, cmtParams.Org, cmtParams.Repo, cmtParams.SHA1, state, status.Context, description, targetURL); err != nil {
+		return fmt.Errorf("set a commit status: %w", err)
+	}
+	return nil
+}
+
+// getStatusState returns the state of the commit status by the exit code.
+// If states doesn't have the exit code, the state is "success" if the exit code is 0, otherwise "failure".
+func getStatusState(states map[int]string, exitCode int) (string, error) {
+	state, ok := states[exitCode]
+	if !ok {
+		if exitCode == 0 {
+			return "success", nil
+		}
+		return "failure", nil
+	}
+	switch state {
+	case "error", "failure", "pending", "success":
+		return state, nil
+	default:
+		return "", fmt.Errorf("status.states has an invalid state %q: the state must be one of error, failure, pending, and success", state)
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getStatusState(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		states   map[int]string
+		exitCode int
+		exp      string
+		isErr    bool
+	}{
+		{
+			title: "default success",
+			exp:   "success",
+		},
+		{
+			title:    "default failure",
+			exitCode: 1,
+			exp:      "failure",
+		},
+		{
+			title:    "mapped",
+			states:   map[int]string{2: "error", 3: "pending"},
+			exitCode: 2,
+			exp:      "error",
+		},
+		{
+			title:    "not mapped",
+			states:   map[int]string{2: "error"},
+			exitCode: 1,
+			exp:      "failure",
+		},
+		{
+			title:  "invalid state",
+			states: map[int]string{0: "ok"},
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			state, err := getStatusState(d.states, d.exitCode)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, state)
+		})
+	}
+}
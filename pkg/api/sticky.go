@@ -0,0 +1,13 @@
+package api
+
+import "fmt"
+
+// stickyUpdateCondition returns the update condition which matches the sticky comment with the name.
+// The name is embedded in the metadata as Sticky.
+// If templateKey isn't empty, the template key must also match.
+func stickyUpdateCondition(name, templateKey string) string {
+	if templateKey == "" {
+		return fmt.Sprintf("Comment.HasMeta && Comment.Meta.Sticky == %q", name)
+	}
+	return fmt.Sprintf("Comment.HasMeta && Comment.Meta.Sticky == %q && Comment.Meta.TemplateKey == %q", name, templateKey)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+type SuggestController struct {
+	Stdin    io.Reader
+	GitHub   GitHub
+	Platform Platform
+	Config   *config.Config
+}
+
+// Suggest submits suggested changes as a single pull request review.
+// Suggestions are read from a JSON array of {file, line, original, replacement}.
+func (ctrl *SuggestController) Suggest(ctx context.Context, opts *option.SuggestOptions) error {
+	if ctrl.Platform != nil {
+		if err := ctrl.Platform.ComplementSuggest(opts); err != nil {
+			return fmt.Errorf("failed to complement opts with platform built in environment variables: %w", err)
+		}
+	}
+
+	cfg := ctrl.Config
+	if cfg.Base != nil {
+		if opts.Org == "" {
+			opts.Org = cfg.Base.Org
+		}
+		if opts.Repo == "" {
+			opts.Repo = cfg.Base.Repo
+		}
+	}
+
+	if err := option.ValidateSuggest(opts); err != nil {
+		return fmt.Errorf("opts is invalid: %w", err)
+	}
+
+	suggestions, err := ctrl.readSuggestions(opts.SuggestionsFile)
+	if err != nil {
+		return err
+	}
+	if len(suggestions) == 0 {
+		logrus.Info("no suggestion is submitted because suggestions are empty")
+		return nil
+	}
+	if err := ctrl.GitHub.CreateSuggestionReview(ctx, opts.Org, opts.Repo, opts.PRNumber, opts.Body, suggestions); err != nil {
+		return fmt.Errorf("submit suggestions: %w", err)
+	}
+	return nil
+}
+
+func (ctrl *SuggestController) readSuggestions(p string) ([]*github.Suggestion, error) {
+	var r io.Reader
+	if p == "-" {
+		r = ctrl.Stdin
+	} else {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("open a suggestions file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	suggestions := []*github.Suggestion{}
+	if err := json.NewDecoder(r).Decode(&suggestions); err != nil {
+		return nil, fmt.Errorf("parse suggestions as JSON: %w", err)
+	}
+	for _, s := range suggestions {
+		if s.File == "" || s.Line <= 0 {
+			return nil, errors.New("file and line are required for suggestions")
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// Summary is a parent comment which lists links to child comments posted with the same key.
+// This is useful to navigate comments posted by jobs of a matrix workflow.
+type Summary struct {
+	Key string
+	// Label is the label of the link to the posted comment
+	Label string
+}
+
+// newSummary returns Summary. If key is empty, nil is returned.
+// The label of the link is the target. If the target is empty, the template key is used.
+// If the matrix context is given, the matrix is appended to the label so that jobs of a matrix workflow don't overwrite links each other.
+func newSummary(key, templateKey, target string, matrix interface{}) *Summary {
+	if key == "" {
+		return nil
+	}
+	label := target
+	if label == "" {
+		label = templateKey
+	}
+	if m := formatMatrix(matrix); m != "" {
+		label += "/" + m
+	}
+	return &Summary{
+		Key:   key,
+		Label: label,
+	}
+}
+
+// formatMatrix formats the matrix context such as "os=linux,version=1.0".
+// Keys are sorted so that the result is stable.
+func formatMatrix(matrix interface{}) string {
+	switch m := matrix.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = fmt.Sprintf("%s=%v", k, m[k])
+		}
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(m)
+	}
+}
+
+// updateSummary records the link to the posted comment into the summary comment and re-renders the summary comment.
+// The summary comment is found by the embedded metadata SummaryKey. If it isn't found, a new summary comment is created.
+// The summary comment is read and written without a lock, so links may be lost if jobs post comments at the same time.
+func (ctrl *CommentController) updateSummary(ctx context.Context, cmt *github.Comment) error {
+	if cmt.PRNumber == 0 {
+		logrus.WithFields(logrus.Fields{
+			"summary": ctrl.Summary.Key,
+		}).Warn("the summary comment isn't updated because no pull request is found")
+		return nil
+	}
+	comments, err := ctrl.GitHub.ListComments(ctx, &github.PullRequest{
+		Org:      cmt.Org,
+		Repo:     cmt.Repo,
+		PRNumber: cmt.PRNumber,
+	})
+	if err != nil {
+		return fmt.Errorf("list issue or pull request comments to find the summary comment: %w", err)
+	}
+	var commentID int64
+	links := map[string]interface{}{}
+	for _, comment := range comments {
+		metadata := map[string]interface{}{}
+		if !extractMetaFromComment(comment.Body, &metadata, ctrl.MetadataSchema) {
+			continue
+		}
+		if key, ok := metadata["SummaryKey"].(string); !ok || key != ctrl.Summary.Key {
+			continue
+		}
+		// the latest summary comment is used
+		commentID = comment.DatabaseID
+		if a, ok := metadata["Links"].(map[string]interface{}); ok {
+			links = a
+		}
+	}
+	links[ctrl.Summary.Label] = cmt.URL
+
+	// SHA1 isn't embedded because the summary comment lists links of all commits
+	// and it shouldn't be hidden by hide conditions such as `Comment.Meta.SHA1 != Commit.SHA1`
+	embeddedComment, err := ctrl.getEmbeddedComment(map[string]interface{}{
+		"SummaryKey": ctrl.Summary.Key,
+		"Links":      links,
+	})
+	if err != nil {
+		return fmt.Errorf("embed the metadata into the summary comment: %w", err)
+	}
+	body := renderSummary(ctrl.Summary.Key, links) + "\n" + embeddedComment
+	if err := ctrl.GitHub.CreateComment(ctx, &github.Comment{
+		PRNumber:       cmt.PRNumber,
+		Org:            cmt.Org,
+		Repo:           cmt.Repo,
+		SHA1:           cmt.SHA1,
+		CommentID:      commentID,
+		Body:           body,
+		BodyForTooLong: body,
+	}); err != nil {
+		return fmt.Errorf("send the summary comment: %w", err)
+	}
+	return nil
+}
+
+// renderSummary renders the index of the summary comment. Links are sorted by labels.
+func renderSummary(key string, links map[string]interface{}) string {
+	labels := make([]string, 0, len(links))
+	for label := range links {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	lines := make([]string, 0, len(labels)+2) //nolint:gomnd
+	lines = append(lines, "## "+key, "")
+	for _, label := range labels {
+		u, _ := links[label].(string)
+		if u == "" {
+			lines = append(lines, "- "+label)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- [%s](%s)", label, u))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func Test_renderSummary(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		links map[string]interface{}
+		exp   string
+	}{
+		{
+			title: "no link",
+			exp:   "## test\n",
+		},
+		{
+			title: "links are sorted by labels",
+			links: map[string]interface{}{
+				"test/os=windows": "https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-2",
+				"test/os=linux":   "https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-1",
+				"dry-run":         "",
+			},
+			exp: `## test
+
+- dry-run
+- [test/os=linux](https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-1)
+- [test/os=windows](https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-2)`,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, renderSummary("test", d.links))
+		})
+	}
+}
+
+func Test_newSummary(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title       string
+		key         string
+		templateKey string
+		target      string
+		matrix      interface{}
+		exp         *Summary
+	}{
+		{
+			title: "no key",
+		},
+		{
+			title:       "the target is used as the label",
+			key:         "test",
+			templateKey: "default",
+			target:      "foo",
+			exp:         &Summary{Key: "test", Label: "foo"},
+		},
+		{
+			title:       "the template key is used if the target is empty",
+			key:         "test",
+			templateKey: "default",
+			exp:         &Summary{Key: "test", Label: "default"},
+		},
+		{
+			title:       "the matrix is appended to the label",
+			key:         "test",
+			templateKey: "default",
+			target:      "test",
+			matrix: map[string]interface{}{
+				"version": 1.0,
+				"os":      "linux",
+			},
+			exp: &Summary{Key: "test", Label: "test/os=linux,version=1"},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, newSummary(d.key, d.templateKey, d.target, d.matrix))
+		})
+	}
+}
+
+func TestCommentController_updateSummary(t *testing.T) {
+	t.Parallel()
+	gh := &fakeGitHub{
+		comments: []*github.IssueComment{
+			{
+				DatabaseID: 10,
+				Body:       "## test\n<!-- github-comment: {\"SummaryKey\":\"test\",\"Links\":{\"test/os=linux\":\"https://example.com/1\"}} -->",
+			},
+		},
+	}
+	ctrl := &CommentController{
+		GitHub:  gh,
+		Summary: &Summary{Key: "test", Label: "test/os=windows"},
+	}
+	require.Nil(t, ctrl.updateSummary(context.Background(), &github.Comment{
+		Org:      "suzuki-shunsuke",
+		Repo:     "github-comment",
+		PRNumber: 1,
+		SHA1:     "abc",
+		URL:      "https://example.com/2",
+	}))
+	require.NotNil(t, gh.createdComment)
+	require.Equal(t, int64(10), gh.createdComment.CommentID)
+	metadata := map[string]interface{}{}
+	require.True(t, extractMetaFromComment(gh.createdComment.Body, &metadata, nil))
+	require.Equal(t, map[string]interface{}{
+		"SummaryKey": "test",
+		"Links": map[string]interface{}{
+			"test/os=linux":   "https://example.com/1",
+			"test/os=windows": "https://example.com/2",
+		},
+	}, metadata)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// getTarget returns the comment target.
+// --target takes precedence over --var target:<target>.
+// If neither is set and autoTarget is true, the target is derived from the job and the matrix context returned by getMatrix,
+// and the second return value is true.
+func getTarget(target string, vars map[string]string, autoTarget bool, getenv func(string) string, matrix interface{}) (string, bool) {
+	if target != "" {
+		return target, false
+	}
+	if target := vars["target"]; target != "" {
+		return target, false
+	}
+	if !autoTarget {
+		return "", false
+	}
+	return deriveTarget(getenv("GITHUB_JOB"), matrix), true
+}
+
+// deriveTarget derives a comment target from the GitHub Actions job id and the matrix context.
+// If the job id is empty, an empty string is returned.
+func deriveTarget(job string, matrix interface{}) string {
+	if job == "" {
+		return ""
+	}
+	m, ok := matrix.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return job
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, m[k])
+	}
+	return job + "/" + strings.Join(pairs, ",")
+}
+
+// targetUpdateCondition returns the update condition which matches comments with the target.
+func targetUpdateCondition(target string) string {
+	return fmt.Sprintf("Comment.HasMeta && Comment.Meta.Target == %q", target)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_deriveTarget(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title      string
+		matrixJSON string
+		env        map[string]string
+		exp        string
+	}{
+		{
+			title: "not GitHub Actions",
+			env: map[string]string{
+				"MATRIX_CONTEXT": `{"os": "ubuntu-latest"}`,
+			},
+		},
+		{
+			title: "no matrix",
+			env: map[string]string{
+				"GITHUB_JOB": "test",
+			},
+			exp: "test",
+		},
+		{
+			title: "GITHUB_COMMENT_MATRIX",
+			env: map[string]string{
+				"GITHUB_JOB":            "test",
+				"GITHUB_COMMENT_MATRIX": `{"os": "ubuntu-latest", "go": 1.19}`,
+			},
+			exp: "test/go=1.19,os=ubuntu-latest",
+		},
+		{
+			title: "MATRIX_CONTEXT",
+			env: map[string]string{
+				"GITHUB_JOB":            "test",
+				"MATRIX_CONTEXT":        `{"os": "macos-latest"}`,
+				"GITHUB_COMMENT_MATRIX": `{"os": "ubuntu-latest"}`,
+			},
+			exp: "test/os=macos-latest",
+		},
+		{
+			title:      "--matrix-json",
+			matrixJSON: `{"os": "windows-latest"}`,
+			env: map[string]string{
+				"GITHUB_JOB":     "test",
+				"MATRIX_CONTEXT": `{"os": "macos-latest"}`,
+			},
+			exp: "test/os=windows-latest",
+		},
+		{
+			title: "null matrix",
+			env: map[string]string{
+				"GITHUB_JOB":     "test",
+				"MATRIX_CONTEXT": `null`,
+			},
+			exp: "test",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			getenv := func(k string) string {
+				return d.env[k]
+			}
+			matrix, err := getMatrix(d.matrixJSON, getenv)
+			require.Nil(t, err)
+			require.Equal(t, d.exp, deriveTarget(getenv("GITHUB_JOB"), matrix))
+		})
+	}
+}
+
+func Test_getTarget(t *testing.T) {
+	t.Parallel()
+	env := map[string]string{
+		"GITHUB_JOB": "test",
+	}
+	data := []struct {
+		title      string
+		target     string
+		vars       map[string]string
+		autoTarget bool
+		matrix     interface{}
+		exp        string
+		derived    bool
+	}{
+		{
+			title: "not set",
+		},
+		{
+			title:      "--target",
+			target:     "foo",
+			vars:       map[string]string{"target": "bar"},
+			autoTarget: true,
+			exp:        "foo",
+		},
+		{
+			title:      "--var target",
+			vars:       map[string]string{"target": "bar"},
+			autoTarget: true,
+			exp:        "bar",
+		},
+		{
+			title: "auto_target is disabled",
+			vars:  map[string]string{"name": "bar"},
+		},
+		{
+			title:      "auto_target",
+			vars:       map[string]string{"name": "bar"},
+			autoTarget: true,
+			exp:        "test",
+			derived:    true,
+		},
+		{
+			title:      "auto_target with matrix",
+			autoTarget: true,
+			matrix:     map[string]interface{}{"os": "ubuntu-latest"},
+			exp:        "test/os=ubuntu-latest",
+			derived:    true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			target, derived := getTarget(d.target, d.vars, d.autoTarget, func(k string) string {
+				return env[k]
+			}, d.matrix)
+			require.Equal(t, d.exp, target)
+			require.Equal(t, d.derived, derived)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+// branchEnvs are environment variables of CI where the branch name is set.
+// They take precedence over git because CI often checks out the commit in detached HEAD.
+var branchEnvs = []string{ //nolint:gochecknoglobals
+	"GITHUB_HEAD_REF",
+	"GITHUB_REF_NAME",
+	"CIRCLE_BRANCH",
+	"DRONE_SOURCE_BRANCH",
+	"BITBUCKET_BRANCH",
+	"CODEBUILD_WEBHOOK_HEAD_REF",
+}
+
+// getBranch returns the current branch name.
+// If the branch isn't found in CI built in environment variables, `git rev-parse --abbrev-ref HEAD` is run.
+func getBranch(ctx context.Context, getenv func(string) string) (string, error) {
+	for _, name := range branchEnvs {
+		if v := getenv(name); v != "" {
+			return strings.TrimPrefix(v, "refs/heads/"), nil
+		}
+	}
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("get the current branch by git: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// templateKeyFromBranch returns the template key of the first rule which matches with the branch.
+// If no rule matches, an empty string is returned.
+func templateKeyFromBranch(rules []*config.TemplateKeyRule, branch string) (string, error) {
+	for _, rule := range rules {
+		matched, err := regexp.MatchString(rule.Branch, branch)
+		if err != nil {
+			return "", fmt.Errorf("compile a branch regular expression of template_key_rules %s: %w", rule.Branch, err)
+		}
+		if matched {
+			return rule.TemplateKey, nil
+		}
+	}
+	return "", nil
+}
+
+// templateKeyFromGit derives the template key from the current branch by template_key_rules.
+// If no rule matches, an empty string is returned.
+func templateKeyFromGit(ctx context.Context, rules []*config.TemplateKeyRule, getenv func(string) string) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+	branch, err := getBranch(ctx, getenv)
+	if err != nil {
+		return "", err
+	}
+	key, err := templateKeyFromBranch(rules, branch)
+	if err != nil {
+		return "", err
+	}
+	logrus.WithFields(logrus.Fields{
+		"branch":       branch,
+		"template_key": key,
+	}).Debug("derive the template key from the branch")
+	return key, nil
+}
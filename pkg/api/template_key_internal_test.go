@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+)
+
+func Test_templateKeyFromBranch(t *testing.T) {
+	t.Parallel()
+	rules := []*config.TemplateKeyRule{
+		{
+			Branch:      "^deploy/prod$",
+			TemplateKey: "prod",
+		},
+		{
+			Branch:      "^deploy/",
+			TemplateKey: "deploy",
+		},
+	}
+	data := []struct {
+		title  string
+		rules  []*config.TemplateKeyRule
+		branch string
+		exp    string
+		isErr  bool
+	}{
+		{
+			title:  "first matched rule is used",
+			rules:  rules,
+			branch: "deploy/prod",
+			exp:    "prod",
+		},
+		{
+			title:  "prefix",
+			rules:  rules,
+			branch: "deploy/staging",
+			exp:    "deploy",
+		},
+		{
+			title:  "no rule matches",
+			rules:  rules,
+			branch: "main",
+		},
+		{
+			title: "invalid regular expression",
+			rules: []*config.TemplateKeyRule{
+				{
+					Branch: "(",
+				},
+			},
+			branch: "main",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			key, err := templateKeyFromBranch(d.rules, d.branch)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, key)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
+)
+
+const (
+	TemplatesOutputFormatText = "text"
+	TemplatesOutputFormatJSON = "json"
+	// maxTemplatePreviewLength is the max number of characters of a template preview
+	maxTemplatePreviewLength = 60
+)
+
+// TemplateInfo is a template key defined in the configuration file or a built-in template.
+type TemplateInfo struct {
+	// Kind is one of post, exec, template, and builtin.
+	// post and exec are template keys of post and exec, and template and builtin are named templates which can be used in other templates
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Preview string `json:"preview"`
+}
+
+type TemplatesController struct {
+	Stdout io.Writer
+	Config *config.Config
+}
+
+// List outputs all template keys and named templates with a short preview.
+func (ctrl *TemplatesController) List(ctx context.Context, format string) error {
+	infos := listTemplates(ctrl.Config)
+	switch format {
+	case "", TemplatesOutputFormatText:
+		w := tabwriter.NewWriter(ctrl.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+		fmt.Fprintln(w, "KIND\tKEY\tPREVIEW")
+		for _, info := range infos {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", info.Kind, info.Key, info.Preview)
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("output templates: %w", err)
+		}
+		return nil
+	case TemplatesOutputFormatJSON:
+		encoder := json.NewEncoder(ctrl.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(infos); err != nil {
+			return fmt.Errorf("output templates as JSON: %w", err)
+		}
+		return nil
+	default:
+		return errors.New("invalid output format: " + format)
+	}
+}
+
+func listTemplates(cfg *config.Config) []*TemplateInfo {
+	infos := []*TemplateInfo{}
+	for _, key := range sortedKeys(cfg.Post) {
+		infos = append(infos, &TemplateInfo{
+			Kind:    "post",
+			Key:     key,
+			Preview: previewTemplate(cfg.Post[key].Template),
+		})
+	}
+	for _, key := range sortedKeys(cfg.Exec) {
+		preview := ""
+		for _, execConfig := range cfg.Exec[key] {
+			if !execConfig.DontComment && execConfig.Template != "" {
+				preview = previewTemplate(execConfig.Template)
+				break
+			}
+		}
+		infos = append(infos, &TemplateInfo{
+			Kind:    "exec",
+			Key:     key,
+			Preview: preview,
+		})
+	}
+	templates := template.GetTemplates(&template.ParamGetTemplates{
+		Templates: cfg.Templates,
+		Lang:      cfg.Lang,
+	})
+	for _, key := range sortedKeys(templates) {
+		kind := "builtin"
+		if _, ok := cfg.Templates[key]; ok {
+			kind = "template"
+		}
+		infos = append(infos, &TemplateInfo{
+			Kind:    kind,
+			Key:     key,
+			Preview: previewTemplate(templates[key]),
+		})
+	}
+	return infos
+}
+
+// previewTemplate returns the first non empty line of the template.
+// It's shortened if it's too long.
+func previewTemplate(tpl string) string {
+	for _, line := range strings.Split(tpl, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > maxTemplatePreviewLength {
+			return string(runes[:maxTemplatePreviewLength]) + "..."
+		}
+		return line
+	}
+	return ""
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_previewTemplate(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		tpl   string
+		exp   string
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "first non empty line",
+			tpl:   "\n  {{template \"link\" .}}\nfoo",
+			exp:   `{{template "link" .}}`,
+		},
+		{
+			title: "too long",
+			tpl:   strings.Repeat("あ", 100),
+			exp:   strings.Repeat("あ", maxTemplatePreviewLength) + "...",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, previewTemplate(d.tpl))
+		})
+	}
+}
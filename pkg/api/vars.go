@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// varRefPattern matches references to other variables such as ${repo}.
+// $${repo} is an escaped reference, which is replaced with the literal ${repo}.
+var varRefPattern = regexp.MustCompile(`\$?\$\{([^}]+)\}`)
+
+var errVarCycle = errors.New("variables reference each other cyclically")
+
+// interpolateVars resolves references ${name} in string values of vars to values of other variables.
+// References are resolved after all variables are collected, so the order of --var flags doesn't matter.
+// If allowUndefined is true, references to undefined variables are left as is.
+// $${name} is replaced with the literal ${name}.
+func interpolateVars(vars map[string]interface{}, allowUndefined bool) error {
+	resolver := &varResolver{
+		vars:           vars,
+		allowUndefined: allowUndefined,
+		resolved:       make(map[string]string, len(vars)),
+		resolving:      map[string]struct{}{},
+	}
+	keys := make([]string, 0, len(vars))
+	for k, v := range vars {
+		if _, ok := v.(string); ok {
+			keys = append(keys, k)
+		}
+	}
+	// sort keys to make the error message stable
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := resolver.resolve(k)
+		if err != nil {
+			return err
+		}
+		vars[k] = v
+	}
+	return nil
+}
+
+type varResolver struct {
+	vars           map[string]interface{}
+	allowUndefined bool
+	resolved       map[string]string
+	resolving      map[string]struct{}
+}
+
+func (r *varResolver) resolve(name string) (string, error) {
+	if v, ok := r.resolved[name]; ok {
+		return v, nil
+	}
+	if _, ok := r.resolving[name]; ok {
+		return "", fmt.Errorf("%w: %s", errVarCycle, name)
+	}
+	val, ok := r.vars[name]
+	if !ok {
+		return "", fmt.Errorf("the variable %s is undefined", name)
+	}
+	s, ok := val.(string)
+	if !ok {
+		// structured variables are referred as is
+		s = fmt.Sprint(val)
+		r.resolved[name] = s
+		return s, nil
+	}
+	r.resolving[name] = struct{}{}
+	defer delete(r.resolving, name)
+
+	var b strings.Builder
+	last := 0
+	for _, idx := range varRefPattern.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(s[last:idx[0]])
+		last = idx[1]
+		if strings.HasPrefix(s[idx[0]:idx[1]], "$$") {
+			b.WriteString(s[idx[0]+1 : idx[1]])
+			continue
+		}
+		ref := s[idx[2]:idx[3]]
+		if _, ok := r.vars[ref]; !ok {
+			if r.allowUndefined {
+				b.WriteString(s[idx[0]:idx[1]])
+				continue
+			}
+			return "", fmt.Errorf("the variable %s refers to the undefined variable %s", name, ref)
+		}
+		v, err := r.resolve(ref)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+	}
+	b.WriteString(s[last:])
+	r.resolved[name] = b.String()
+	return b.String(), nil
+}
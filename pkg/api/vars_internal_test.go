@@ -0,0 +1,82 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_interpolateVars(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title          string
+		vars           map[string]interface{}
+		allowUndefined bool
+		exp            map[string]interface{}
+		isErr          bool
+	}{
+		{
+			title: "no reference",
+			vars:  map[string]interface{}{"foo": "bar", "n": 1},
+			exp:   map[string]interface{}{"foo": "bar", "n": 1},
+		},
+		{
+			title: "nested reference",
+			vars: map[string]interface{}{
+				"org":      "suzuki-shunsuke",
+				"repo":     "github-comment",
+				"repo_url": "${server}/${org}/${repo}",
+				"server":   "https://github.com",
+				"n":        3,
+				"pr_url":   "${repo_url}/pull/${n}",
+			},
+			exp: map[string]interface{}{
+				"org":      "suzuki-shunsuke",
+				"repo":     "github-comment",
+				"repo_url": "https://github.com/suzuki-shunsuke/github-comment",
+				"server":   "https://github.com",
+				"n":        3,
+				"pr_url":   "https://github.com/suzuki-shunsuke/github-comment/pull/3",
+			},
+		},
+		{
+			title: "cycle",
+			vars:  map[string]interface{}{"a": "${b}", "b": "${a}"},
+			isErr: true,
+		},
+		{
+			title: "self reference",
+			vars:  map[string]interface{}{"a": "x${a}"},
+			isErr: true,
+		},
+		{
+			title: "undefined",
+			vars:  map[string]interface{}{"a": "${b}"},
+			isErr: true,
+		},
+		{
+			title:          "allow undefined",
+			vars:           map[string]interface{}{"a": "${b}-${c}", "c": "foo"},
+			allowUndefined: true,
+			exp:            map[string]interface{}{"a": "${b}-foo", "c": "foo"},
+		},
+		{
+			title: "escape",
+			vars:  map[string]interface{}{"a": "echo $${HOME} ${b}", "b": "foo", "c": "$${b}/${a}"},
+			exp:   map[string]interface{}{"a": "echo ${HOME} foo", "b": "foo", "c": "${b}/echo ${HOME} foo"},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := interpolateVars(d.vars, d.allowUndefined)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, d.vars)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -44,9 +45,8 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Usage: "GitHub repository name",
 					},
 					&cli.StringFlag{
-						Name:    "token",
-						Usage:   "GitHub API token",
-						EnvVars: []string{"GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN"},
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
 					},
 					&cli.StringFlag{
 						Name:  "sha1",
@@ -56,15 +56,35 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Name:  "template",
 						Usage: "comment template",
 					},
+					&cli.StringFlag{
+						Name:  "template-file",
+						Usage: "a file path of the comment template. This can't be used with --template and --template-key",
+					},
 					&cli.StringFlag{
 						Name:    "template-key",
 						Aliases: []string{"k"},
 						Usage:   "comment template key",
 						Value:   "default",
 					},
+					&cli.StringFlag{
+						Name:  "delims",
+						Usage: `a pair of space-separated delimiters of comment templates such as "<< >>"`,
+					},
+					&cli.BoolFlag{
+						Name:  "comment-key-from-git",
+						Usage: "derive the template key from the current branch by template_key_rules in the configuration file if --template-key isn't set",
+					},
+					&cli.StringFlag{
+						Name:  "lang",
+						Usage: "the language of built-in templates (en, ja). The default is English",
+					},
+					&cli.StringFlag{
+						Name:  "target",
+						Usage: "the comment target. It is embedded in the metadata and can be referred by comment(target) in conditions",
+					},
 					&cli.StringFlag{
 						Name:  "config",
-						Usage: "configuration file path",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
 					},
 					&cli.IntFlag{
 						Name:  "pr",
@@ -78,10 +98,31 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Name:  "var-file",
 						Usage: "template variable name and file path",
 					},
+					&cli.StringSliceFlag{
+						Name:  "var-file-json",
+						Usage: "a JSON file path. The file must be an object and all keys are merged into Vars. Nested keys can be referred as .Vars.foo.bar in templates",
+					},
+					&cli.StringSliceFlag{
+						Name:  "var-file-yaml",
+						Usage: "a YAML file path. The file must be a mapping and all keys are merged into Vars. Nested keys can be referred as .Vars.foo.bar in templates",
+					},
+					&cli.BoolFlag{
+						Name:  "allow-undefined-vars",
+						Usage: "leave references ${name} to undefined variables in variables as is instead of failing. $${name} is replaced with the literal ${name}",
+					},
 					&cli.BoolFlag{
 						Name:  "dry-run",
 						Usage: "output a comment to standard error output instead of posting to GitHub",
 					},
+					&cli.StringFlag{
+						Name:  "dry-run-output",
+						Usage: "a file path where the rendered comment body including the metadata is written in dry-run",
+					},
+					&cli.StringFlag{
+						Name:  "dry-run-output-format",
+						Usage: `the format of --dry-run-output. "body" writes the comment body as is. "json" writes the comment body, the pull request number, and whether the comment is created or updated as JSON`,
+						Value: "body",
+					},
 					&cli.BoolFlag{
 						Name:    "skip-no-token",
 						Aliases: []string{"n"},
@@ -93,15 +134,126 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Aliases: []string{"s"},
 						Usage:   "suppress the output of dry-run and skip-no-token",
 					},
+					&cli.BoolFlag{
+						Name:  "require-pr",
+						Usage: "fail with the exit code 3 if no pull request is found",
+					},
+					&cli.BoolFlag{
+						Name:  "debug-footer",
+						Usage: "append a collapsed section with the template key, the matched config, vars, and the run id for troubleshooting",
+					},
+					&cli.BoolFlag{
+						Name:  "minimize-on-create",
+						Usage: "minimize the comment immediately after the comment is posted",
+					},
+					&cli.StringFlag{
+						Name:  "pr-base",
+						Usage: "comma-separated glob patterns of base branches such as 'main,release/*'. Comments are posted only on pull requests targeting the branches",
+					},
+					&cli.IntFlag{
+						Name:  "pr-comment-limit",
+						Usage: "the max number of comments which github-comment posts to a pull request. The default is max_comments_per_pr in the configuration file or 100",
+					},
+					&cli.StringFlag{
+						Name:  "comment-cooldown-file",
+						Usage: "a file path where times when comments were posted are recorded per template key and target. It's shared across processes",
+					},
+					&cli.DurationFlag{
+						Name:  "comment-cooldown",
+						Usage: "skip posting a comment if a comment with the same template key and target was posted within this duration. Requires --comment-cooldown-file",
+					},
+					&cli.StringFlag{
+						Name:  "comment-footer-file",
+						Usage: "a file path of a footer template which is appended to comments. A relative path is resolved relative to the configuration file",
+					},
+					&cli.StringFlag{
+						Name:  "summary",
+						Usage: "the key of a summary comment. The link to the posted comment is recorded into the summary comment, which lists links to comments posted with the same key. The target or the template key is used as the label",
+					},
+					&cli.StringFlag{
+						Name:  "env-file",
+						Usage: "a dotenv file path. The environment variables are referred in templates and expressions, and passed to the command of exec. They take precedence over the environment variables of the process",
+					},
+					&cli.StringFlag{
+						Name:  "matrix-json",
+						Usage: "the matrix context of GitHub Actions as JSON. It's exposed as .Matrix. The default is the environment variable MATRIX_CONTEXT",
+					},
+					&cli.StringFlag{
+						Name:  "idempotency-key",
+						Usage: "a key embedded in the metadata. If a comment with the same key already exists, the comment isn't posted",
+					},
 					&cli.BoolFlag{
 						Name:  "stdin-template",
 						Usage: "read standard input as the template",
 					},
+					&cli.BoolFlag{
+						Name:  "stdin-json",
+						Usage: "read a JSON document such as {\"template_key\": \"default\", \"vars\": {}, \"pr\": 1} from standard input and merge it into options",
+					},
+					&cli.BoolFlag{
+						Name:  "stdin-vars",
+						Usage: "read variables from standard input line by line in the format KEY=VALUE",
+					},
+					&cli.StringFlag{
+						Name:  "validate-mentions",
+						Usage: `validate mentioned users and teams exist. "warn" or "fail"`,
+					},
+					&cli.StringFlag{
+						Name:  "metadata-out",
+						Usage: "a file path where the embedded metadata is written as JSON when a comment is posted",
+					},
+					&cli.BoolFlag{
+						Name:  "no-metadata",
+						Usage: "don't embed metadata in the comment. The comment can't be updated or hidden by github-comment afterward",
+					},
+					&cli.StringFlag{
+						Name:  "comment-if",
+						Usage: "post the comment only if the expression is true. Commit, TemplateKey, Vars, and Env can be referred. commentExists is true if a comment matching the update condition exists",
+					},
+					&cli.BoolFlag{
+						Name:  "once",
+						Usage: "post the comment only if no comment matching the update condition exists. The existing comment isn't updated",
+					},
 					&cli.StringFlag{
 						Name:    "update-condition",
 						Aliases: []string{"u"},
 						Usage:   "update the comment that matches with the condition",
 					},
+					&cli.StringFlag{
+						Name:    "sticky",
+						Aliases: []string{"update-key"},
+						Usage:   "the name of the sticky comment. The comment with the same name is updated if it exists, otherwise a new comment is created",
+					},
+					&cli.BoolFlag{
+						Name:  "merge-vars-from-comment",
+						Usage: "merge the embedded vars of the updated comment into vars with the lowest precedence",
+					},
+					&cli.BoolFlag{
+						Name:  "append",
+						Usage: "append the body to the updated comment instead of replacing it. Simultaneous appends to the same comment can race, so serialize jobs by a concurrency group or a pull request label as a mutex",
+					},
+					&cli.StringFlag{
+						Name:  "append-delimiter",
+						Usage: "the delimiter between the body of the updated comment and the appended body",
+						Value: "\n",
+					},
+					&cli.BoolFlag{
+						Name:  "repin",
+						Usage: "delete the updated comment and create a new comment so that the comment moves to the bottom",
+					},
+					&cli.DurationFlag{
+						Name:  "repin-cooldown",
+						Usage: "update the comment instead of repinning it if the comment was created within the duration",
+						Value: time.Minute,
+					},
+					&cli.DurationFlag{
+						Name:  "edit-within",
+						Usage: "update only comments created within the duration (e.g. 24h). Otherwise a new comment is created",
+					},
+					&cli.StringFlag{
+						Name:  "comment-id",
+						Usage: "the database id of the comment which is updated. Comments aren't searched by the update condition",
+					},
 				},
 			},
 			{
@@ -118,9 +270,8 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Usage: "GitHub repository name",
 					},
 					&cli.StringFlag{
-						Name:    "token",
-						Usage:   "GitHub API token",
-						EnvVars: []string{"GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN"},
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
 					},
 					&cli.StringFlag{
 						Name:  "sha1",
@@ -131,14 +282,34 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Usage: "comment template",
 					},
 					&cli.StringFlag{
+						Name:  "template-file",
+						Usage: "a file path of the comment template. This can't be used with --template and --template-key",
+					},
+					&cli.StringSliceFlag{
 						Name:    "template-key",
 						Aliases: []string{"k"},
-						Usage:   "comment template key",
-						Value:   "default",
+						Usage:   "comment template key. If it's set multiple times, a comment is posted per template key with the result of a single run",
+						Value:   cli.NewStringSlice("default"),
+					},
+					&cli.StringFlag{
+						Name:  "delims",
+						Usage: `a pair of space-separated delimiters of comment templates such as "<< >>"`,
+					},
+					&cli.BoolFlag{
+						Name:  "comment-key-from-git",
+						Usage: "derive the template key from the current branch by template_key_rules in the configuration file if --template-key isn't set",
+					},
+					&cli.StringFlag{
+						Name:  "lang",
+						Usage: "the language of built-in templates (en, ja). The default is English",
+					},
+					&cli.StringFlag{
+						Name:  "target",
+						Usage: "the comment target. It is embedded in the metadata and can be referred by comment(target) in conditions",
 					},
 					&cli.StringFlag{
 						Name:  "config",
-						Usage: "configuration file path",
+						Usage: `configuration file path. "-" isn't supported because the standard input is passed to the command`,
 					},
 					&cli.IntFlag{
 						Name:  "pr",
@@ -152,10 +323,40 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Name:  "var-file",
 						Usage: "template variable name and file path",
 					},
+					&cli.StringSliceFlag{
+						Name:  "var-file-json",
+						Usage: "a JSON file path. The file must be an object and all keys are merged into Vars. Nested keys can be referred as .Vars.foo.bar in templates",
+					},
+					&cli.StringSliceFlag{
+						Name:  "var-file-yaml",
+						Usage: "a YAML file path. The file must be a mapping and all keys are merged into Vars. Nested keys can be referred as .Vars.foo.bar in templates",
+					},
+					&cli.BoolFlag{
+						Name:  "allow-undefined-vars",
+						Usage: "leave references ${name} to undefined variables in variables as is instead of failing. $${name} is replaced with the literal ${name}",
+					},
+					&cli.BoolFlag{
+						Name:  "merge-vars-from-comment",
+						Usage: "merge the embedded vars of the previous comment with the same template key and target into vars with the lowest precedence",
+					},
+					&cli.StringFlag{
+						Name:    "update-condition",
+						Aliases: []string{"u"},
+						Usage:   "update the comment that matches with the condition. The update condition of the exec config takes precedence over it. commentExists in when is true if a comment matching it exists",
+					},
 					&cli.BoolFlag{
 						Name:  "dry-run",
 						Usage: "output a comment to standard error output instead of posting to GitHub",
 					},
+					&cli.StringFlag{
+						Name:  "dry-run-output",
+						Usage: "a file path where the rendered comment body including the metadata is written in dry-run",
+					},
+					&cli.StringFlag{
+						Name:  "dry-run-output-format",
+						Usage: `the format of --dry-run-output. "body" writes the comment body as is. "json" writes the comment body, the pull request number, and whether the comment is created or updated as JSON`,
+						Value: "body",
+					},
 					&cli.BoolFlag{
 						Name:    "skip-no-token",
 						Aliases: []string{"n"},
@@ -167,6 +368,107 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Aliases: []string{"s"},
 						Usage:   "suppress the output of dry-run and skip-no-token",
 					},
+					&cli.BoolFlag{
+						Name:  "require-pr",
+						Usage: "skip the comment and fail with the exit code 3 if no pull request is found. The command is run anyway",
+					},
+					&cli.BoolFlag{
+						Name:  "debug-footer",
+						Usage: "append a collapsed section with the template key, the matched config, vars, and the run id for troubleshooting",
+					},
+					&cli.BoolFlag{
+						Name:  "minimize-on-create",
+						Usage: "minimize the comment immediately after the comment is posted",
+					},
+					&cli.StringFlag{
+						Name:  "pr-base",
+						Usage: "comma-separated glob patterns of base branches such as 'main,release/*'. Comments are posted only on pull requests targeting the branches",
+					},
+					&cli.IntFlag{
+						Name:  "pr-comment-limit",
+						Usage: "the max number of comments which github-comment posts to a pull request. The default is max_comments_per_pr in the configuration file or 100",
+					},
+					&cli.StringFlag{
+						Name:  "comment-cooldown-file",
+						Usage: "a file path where times when comments were posted are recorded per template key and target. It's shared across processes",
+					},
+					&cli.DurationFlag{
+						Name:  "comment-cooldown",
+						Usage: "skip posting a comment if a comment with the same template key and target was posted within this duration. Requires --comment-cooldown-file",
+					},
+					&cli.StringFlag{
+						Name:  "comment-footer-file",
+						Usage: "a file path of a footer template which is appended to comments. A relative path is resolved relative to the configuration file",
+					},
+					&cli.StringFlag{
+						Name:  "summary",
+						Usage: "the key of a summary comment. The link to the posted comment is recorded into the summary comment, which lists links to comments posted with the same key. The target or the template key is used as the label",
+					},
+					&cli.StringFlag{
+						Name:  "env-file",
+						Usage: "a dotenv file path. The environment variables are referred in templates and expressions, and passed to the command of exec. They take precedence over the environment variables of the process",
+					},
+					&cli.StringFlag{
+						Name:  "matrix-json",
+						Usage: "the matrix context of GitHub Actions as JSON. It's exposed as .Matrix. The default is the environment variable MATRIX_CONTEXT",
+					},
+					&cli.StringFlag{
+						Name:  "idempotency-key",
+						Usage: "a key embedded in the metadata. If a comment with the same key already exists, the comment isn't posted",
+					},
+					&cli.StringFlag{
+						Name:  "validate-mentions",
+						Usage: `validate mentioned users and teams exist. "warn" or "fail"`,
+					},
+					&cli.StringFlag{
+						Name:  "command-file",
+						Usage: "a file path of the shell script which is run with sh -c instead of the command arguments",
+					},
+					&cli.IntFlag{
+						Name:  "exec-retry",
+						Usage: "the max number of times the command is retried until it succeeds. Only the result of the last run is commented",
+					},
+					&cli.DurationFlag{
+						Name:  "exec-retry-delay",
+						Usage: "the delay between retries of the command",
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "bound the whole run of the command including retries. If the command times out, it's killed and the comment is posted with the exit code 124 and the output captured until then",
+					},
+					&cli.StringFlag{
+						Name:  "state-file",
+						Usage: "a file path where results of commands are saved. Results of previous runs are exposed as .Previous in templates",
+					},
+					&cli.BoolFlag{
+						Name:  "comment-on-first-failure-only",
+						Usage: "post a comment only for the first failed command in a session. Requires --session-file",
+					},
+					&cli.StringFlag{
+						Name:    "session-file",
+						Usage:   "a file path where the state of the session is saved. It is shared by exec invocations in the same session",
+						EnvVars: []string{"GITHUB_COMMENT_SESSION_FILE"},
+					},
+					&cli.BoolFlag{
+						Name:  "fail-on-no-match",
+						Usage: "fail if no exec config matches. If the command fails, the exit code of the command takes precedence",
+					},
+					&cli.BoolFlag{
+						Name:  "always-comment",
+						Usage: "post a comment even if a failure has already been commented in the session",
+					},
+					&cli.BoolFlag{
+						Name:  "collect-matched-configs",
+						Usage: "expose names of all matched exec configs as .MatchedConfigs in templates. The comment is still posted based on the first matched config",
+					},
+					&cli.StringFlag{
+						Name:  "metadata-out",
+						Usage: "a file path where the embedded metadata is written as JSON when a comment is posted",
+					},
+					&cli.BoolFlag{
+						Name:  "no-metadata",
+						Usage: "don't embed metadata in the comment. The comment can't be updated or hidden by github-comment afterward",
+					},
 				},
 			},
 			{
@@ -174,6 +476,27 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 				Usage:  "scaffold a configuration file if it doesn't exist",
 				Action: runner.initAction,
 			},
+			{
+				Name:   "config-schema",
+				Usage:  "output the JSON Schema of the configuration file",
+				Action: runner.configSchemaAction,
+			},
+			{
+				Name:   "templates",
+				Usage:  "list template keys and named templates including built-in templates with a short preview",
+				Action: runner.templatesAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
+					},
+					&cli.StringFlag{
+						Name:  "output-format",
+						Usage: "output format. text or json",
+						Value: "text",
+					},
+				},
+			},
 			{
 				Name:   "hide",
 				Usage:  "hide issue or pull request comments",
@@ -188,18 +511,21 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 						Usage: "GitHub repository name",
 					},
 					&cli.StringFlag{
-						Name:    "token",
-						Usage:   "GitHub API token",
-						EnvVars: []string{"GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN"},
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
 					},
 					&cli.StringFlag{
 						Name:  "config",
-						Usage: "configuration file path",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
 					},
 					&cli.StringFlag{
 						Name:  "condition",
 						Usage: "hide condition",
 					},
+					&cli.StringFlag{
+						Name:  "reason-template",
+						Usage: `a template of the reason why comments are hidden. "> folded: <reason>" is prepended to comments before they are hidden`,
+					},
 					&cli.StringFlag{
 						Name:    "hide-key",
 						Aliases: []string{"k"},
@@ -239,6 +565,252 @@ func (runner *Runner) Run(ctx context.Context, args []string) error { //nolint:f
 					},
 				},
 			},
+			{
+				Name:   "suggest",
+				Usage:  "submit suggested changes as a pull request review",
+				Action: runner.suggestAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "org",
+						Usage: "GitHub organization name",
+					},
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "GitHub repository name",
+					},
+					&cli.StringFlag{
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: "configuration file path",
+					},
+					&cli.IntFlag{
+						Name:  "pr",
+						Usage: "GitHub pull request number",
+					},
+					&cli.StringFlag{
+						Name:  "suggestions",
+						Usage: `a JSON file of suggestions [{"file", "line", "original", "replacement"}]. If "-" is given, suggestions are read from the standard input`,
+					},
+					&cli.StringFlag{
+						Name:  "body",
+						Usage: "the review body",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "output a review to standard error output instead of posting to GitHub",
+					},
+					&cli.BoolFlag{
+						Name:    "skip-no-token",
+						Aliases: []string{"n"},
+						Usage:   "works like dry-run if the GitHub Access Token isn't set",
+						EnvVars: []string{"GITHUB_COMMENT_SKIP_NO_TOKEN"},
+					},
+					&cli.BoolFlag{
+						Name:    "silent",
+						Aliases: []string{"s"},
+						Usage:   "suppress the output of dry-run and skip-no-token",
+					},
+				},
+			},
+			{
+				Name:   "prune",
+				Usage:  "delete comments posted by github-comment",
+				Action: runner.pruneAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "org",
+						Usage: "GitHub organization name",
+					},
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "GitHub repository name",
+					},
+					&cli.StringFlag{
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
+					},
+					&cli.IntFlag{
+						Name:  "pr",
+						Usage: "GitHub pull request number",
+					},
+					&cli.StringFlag{
+						Name:    "template-key",
+						Aliases: []string{"k"},
+						Usage:   "delete only comments whose template key is this value",
+					},
+					&cli.StringFlag{
+						Name:  "author",
+						Usage: "delete only comments posted by this user. The default is the authenticated user. It's required if the authenticated user can't be gotten, e.g. with GitHub Actions's GITHUB_TOKEN",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "output comments which would be deleted to standard error output instead of deleting them",
+					},
+					&cli.BoolFlag{
+						Name:    "skip-no-token",
+						Aliases: []string{"n"},
+						Usage:   "works like dry-run if the GitHub Access Token isn't set",
+						EnvVars: []string{"GITHUB_COMMENT_SKIP_NO_TOKEN"},
+					},
+					&cli.BoolFlag{
+						Name:    "silent",
+						Aliases: []string{"s"},
+						Usage:   "suppress the output of dry-run and skip-no-token",
+					},
+				},
+			},
+			{
+				Name:   "resolve-threads",
+				Usage:  "minimize review comments posted by github-comment whose threads are resolved",
+				Action: runner.resolveThreadsAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "org",
+						Usage: "GitHub organization name",
+					},
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "GitHub repository name",
+					},
+					&cli.StringFlag{
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
+					},
+					&cli.IntFlag{
+						Name:  "pr",
+						Usage: "GitHub pull request number",
+					},
+					&cli.StringFlag{
+						Name:    "template-key",
+						Aliases: []string{"k"},
+						Usage:   "handle only comments whose template key is this value",
+					},
+					&cli.BoolFlag{
+						Name:  "resolve-outdated",
+						Usage: "resolve unresolved outdated threads started by github-comment and minimize their comments",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "output threads and comments which would be changed to standard error output instead of changing them",
+					},
+					&cli.BoolFlag{
+						Name:    "skip-no-token",
+						Aliases: []string{"n"},
+						Usage:   "works like dry-run if the GitHub Access Token isn't set",
+						EnvVars: []string{"GITHUB_COMMENT_SKIP_NO_TOKEN"},
+					},
+					&cli.BoolFlag{
+						Name:    "silent",
+						Aliases: []string{"s"},
+						Usage:   "suppress the output of dry-run and skip-no-token",
+					},
+				},
+			},
+			{
+				Name:   "react",
+				Usage:  "add a reaction to the comment which matches with the update condition",
+				Action: runner.reactAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "org",
+						Usage: "GitHub organization name",
+					},
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "GitHub repository name",
+					},
+					&cli.StringFlag{
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
+					},
+					&cli.StringFlag{
+						Name:  "sha1",
+						Usage: "commit sha1",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
+					},
+					&cli.IntFlag{
+						Name:  "pr",
+						Usage: "GitHub pull request number",
+					},
+					&cli.StringFlag{
+						Name:    "template-key",
+						Aliases: []string{"k"},
+						Usage:   "comment template key. The update condition of the template is used to find the comment",
+						Value:   "default",
+					},
+					&cli.StringFlag{
+						Name:  "content",
+						Usage: "reaction. +1, -1, laugh, confused, heart, hooray, rocket, or eyes",
+					},
+					&cli.StringFlag{
+						Name:    "update-condition",
+						Aliases: []string{"u"},
+						Usage:   "the condition of the comment which the reaction is added to. The default is the update condition of the template or the condition which matches comments with the template key",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "output the reaction to standard error output instead of adding it",
+					},
+					&cli.BoolFlag{
+						Name:    "skip-no-token",
+						Aliases: []string{"n"},
+						Usage:   "works like dry-run if the GitHub Access Token isn't set",
+						EnvVars: []string{"GITHUB_COMMENT_SKIP_NO_TOKEN"},
+					},
+					&cli.BoolFlag{
+						Name:    "silent",
+						Aliases: []string{"s"},
+						Usage:   "suppress the output of dry-run and skip-no-token",
+					},
+				},
+			},
+			{
+				Name:   "list",
+				Usage:  "list comments of the pull request and their metadata to write update and hide conditions",
+				Action: runner.listAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "org",
+						Usage: "GitHub organization name",
+					},
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "GitHub repository name",
+					},
+					&cli.StringFlag{
+						Name:  "token",
+						Usage: "GitHub API token. In case of GitLab, GitLab API token. If it isn't set, the environment variable GITHUB_TOKEN or GITHUB_ACCESS_TOKEN is used. In case of GitLab, GITLAB_TOKEN takes precedence over them",
+					},
+					&cli.StringFlag{
+						Name:  "config",
+						Usage: `configuration file path. If "-" is given, the configuration is read from the standard input`,
+					},
+					&cli.IntFlag{
+						Name:  "pr",
+						Usage: "GitHub pull request number",
+					},
+					&cli.StringFlag{
+						Name:    "output-format",
+						Aliases: []string{"output"},
+						Usage:   "output format. text or json",
+						Value:   "text",
+					},
+				},
+			},
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
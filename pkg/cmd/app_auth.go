@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// getAppParam returns credentials of the GitHub App installation configured by cfg.App.
+// The private key path is relative to the configuration file.
+func getAppParam(cfg *config.Config) (*github.AppParam, error) {
+	app := cfg.App
+	if app.AppID == 0 || app.InstallationID == 0 || app.PrivateKeyPath == "" {
+		return nil, errors.New("app.app_id, app.installation_id, and app.private_key_path are required")
+	}
+	p := app.PrivateKeyPath
+	if !filepath.IsAbs(p) && cfg.Path != "" {
+		p = filepath.Join(filepath.Dir(cfg.Path), p)
+	}
+	key, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("read the private key of the GitHub App %s: %w", p, err)
+	}
+	return &github.AppParam{
+		AppID:          app.AppID,
+		InstallationID: app.InstallationID,
+		PrivateKey:     key,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/envfile"
+)
+
+// loadEnvFile reads the env file and returns Getenv and the environment variables of the command.
+// The environment variables in the file take precedence over the process's environment variables,
+// but the process's environment isn't changed.
+// If the file path is empty, os.Getenv and os.Environ() are returned.
+func loadEnvFile(p string) (func(string) string, []string, error) {
+	if p == "" {
+		return os.Getenv, os.Environ(), nil
+	}
+	envs, err := envfile.Read(p)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+	environ := os.Environ()
+	for k, v := range envs {
+		// If a key is duplicated, the last value is used
+		environ = append(environ, k+"="+v)
+	}
+	return func(k string) string {
+		if v, ok := envs[k]; ok {
+			return v
+		}
+		return os.Getenv(k)
+	}, environ, nil
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 
@@ -11,7 +13,6 @@ import (
 	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
 	"github.com/suzuki-shunsuke/github-comment/pkg/platform"
-	"github.com/suzuki-shunsuke/github-comment/pkg/template"
 	"github.com/urfave/cli/v2"
 )
 
@@ -21,14 +22,62 @@ func parseExecOptions(opts *option.ExecOptions, c *cli.Context) error {
 	opts.Token = c.String("token")
 	opts.SHA1 = c.String("sha1")
 	opts.Template = c.String("template")
-	opts.TemplateKey = c.String("template-key")
+	if p := c.String("template-file"); p != "" {
+		if c.IsSet("template") || c.IsSet("template-key") {
+			return errors.New("template-file can't be used with template and template-key")
+		}
+		tpl, err := readTemplateFile(p)
+		if err != nil {
+			return err
+		}
+		opts.Template = tpl
+	}
+	if keys := c.StringSlice("template-key"); len(keys) != 0 {
+		opts.TemplateKey = keys[0]
+		opts.ExtraTemplateKeys = keys[1:]
+	}
+	// the template key is derived from the branch only if --template-key isn't set
+	opts.TemplateKeyFromGit = c.Bool("comment-key-from-git") && !c.IsSet("template-key")
+	opts.Target = c.String("target")
+	opts.Lang = c.String("lang")
 	opts.ConfigPath = c.String("config")
+	if opts.ConfigPath == config.StdinPath {
+		// the standard input is passed to the command
+		return errors.New("exec can't read the configuration from the standard input")
+	}
 	opts.PRNumber = c.Int("pr")
 	opts.Args = c.Args().Slice()
+	opts.CommandFile = c.String("command-file")
 	opts.DryRun = c.Bool("dry-run")
+	opts.DryRunOutput = c.String("dry-run-output")
+	opts.DryRunOutputFormat = c.String("dry-run-output-format")
 	opts.SkipNoToken = c.Bool("skip-no-token")
 	opts.Silent = c.Bool("silent")
+	opts.RequirePR = c.Bool("require-pr")
+	opts.DebugFooter = c.Bool("debug-footer")
+	opts.MinimizeOnCreate = c.Bool("minimize-on-create")
+	opts.IdempotencyKey = c.String("idempotency-key")
+	opts.PRCommentLimit = c.Int("pr-comment-limit")
+	opts.PRBase = parseCommaSeparated(c.String("pr-base"))
+	opts.CooldownFile = c.String("comment-cooldown-file")
+	opts.FooterFile = c.String("comment-footer-file")
+	opts.EnvFile = c.String("env-file")
+	opts.Summary = c.String("summary")
+	opts.Cooldown = c.Duration("comment-cooldown")
+	opts.MatrixJSON = c.String("matrix-json")
 	opts.LogLevel = c.String("log-level")
+	opts.NoMetadata = c.Bool("no-metadata")
+	opts.ValidateMentions = c.String("validate-mentions")
+	opts.MetadataOut = c.String("metadata-out")
+	opts.StateFile = c.String("state-file")
+	opts.Retry = c.Int("exec-retry")
+	opts.RetryDelay = c.Duration("exec-retry-delay")
+	opts.Timeout = c.Duration("timeout")
+	opts.FirstFailureOnly = c.Bool("comment-on-first-failure-only")
+	opts.SessionFile = c.String("session-file")
+	opts.AlwaysComment = c.Bool("always-comment")
+	opts.FailOnNoMatch = c.Bool("fail-on-no-match")
+	opts.CollectMatchedConfigs = c.Bool("collect-matched-configs")
 
 	vars, err := parseVarsFlag(c.StringSlice("var"))
 	if err != nil {
@@ -42,6 +91,14 @@ func parseExecOptions(opts *option.ExecOptions, c *cli.Context) error {
 		vars[k] = v
 	}
 	opts.Vars = vars
+	structuredVars, err := parseStructuredVarFiles(c.StringSlice("var-file-json"), c.StringSlice("var-file-yaml"))
+	if err != nil {
+		return err
+	}
+	opts.StructuredVars = structuredVars
+	opts.AllowUndefinedVars = c.Bool("allow-undefined-vars")
+	opts.MergeVarsFromComment = c.Bool("merge-vars-from-comment")
+	opts.UpdateCondition = c.String("update-condition")
 
 	return nil
 }
@@ -76,9 +133,17 @@ func (runner *Runner) execAction(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("find and read a configuration file: %w", err)
 	}
+	if err := appendFooterFile(cfg, opts.FooterFile); err != nil {
+		return err
+	}
 	opts.SkipNoToken = opts.SkipNoToken || cfg.SkipNoToken
 	opts.Silent = opts.Silent || cfg.Silent
 
+	getenv, environ, err := loadEnvFile(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("load environment variables from the env file: %w", err)
+	}
+
 	var pt api.Platform = platform.Get()
 
 	gh, err := getGitHub(c.Context, &opts.Options, cfg)
@@ -86,24 +151,38 @@ func (runner *Runner) execAction(c *cli.Context) error {
 		return fmt.Errorf("initialize commenter: %w", err)
 	}
 
+	if delims := c.String("delims"); delims != "" {
+		// the command line option takes precedence over the configuration file.
+		// cfg.Delims is also referred to render exec's built-in default template
+		cfg.Delims = delims
+	}
+	renderer, err := newRenderer(cfg, "", getenv, environ)
+	if err != nil {
+		return err
+	}
+
 	ctrl := api.ExecController{
-		Wd:     wd,
-		Getenv: os.Getenv,
-		Stdin:  runner.Stdin,
-		Stdout: runner.Stdout,
-		Stderr: runner.Stderr,
-		GitHub: gh,
-		Renderer: &template.Renderer{
-			Getenv: os.Getenv,
-		},
+		Wd:       wd,
+		Getenv:   getenv,
+		Stdin:    runner.Stdin,
+		Stdout:   runner.Stdout,
+		Stderr:   runner.Stderr,
+		GitHub:   gh,
+		Renderer: renderer,
 		Executor: &execute.Executor{
 			Stdout: runner.Stdout,
 			Stderr: runner.Stderr,
-			Env:    os.Environ(),
+			Env:    environ,
 		},
 		Expr:     &expr.Expr{},
 		Platform: pt,
 		Config:   cfg,
+		Version:  runner.LDFlags.Version,
+		PreCommentExecutor: &execute.Executor{
+			Stdout: io.Discard,
+			Stderr: io.Discard,
+			Env:    environ,
+		},
 	}
 	return ctrl.Exec(c.Context, opts) //nolint:wrapcheck
 }
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_execAction_configStdin(t *testing.T) {
+	t.Parallel()
+	runner := &Runner{
+		Stdin:   strings.NewReader("exec: {}"),
+		Stdout:  io.Discard,
+		Stderr:  io.Discard,
+		LDFlags: &LDFlags{},
+	}
+	err := runner.Run(context.Background(), []string{"github-comment", "exec", "--config", "-", "--", "true"})
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "standard input")
+}
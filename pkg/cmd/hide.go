@@ -10,6 +10,7 @@ import (
 	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
 	"github.com/suzuki-shunsuke/github-comment/pkg/platform"
+	"github.com/suzuki-shunsuke/github-comment/pkg/template"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/term"
 )
@@ -27,6 +28,7 @@ func parseHideOptions(opts *option.HideOptions, c *cli.Context) error {
 	opts.LogLevel = c.String("log-level")
 	opts.HideKey = c.String("hide-key")
 	opts.Condition = c.String("condition")
+	opts.ReasonTemplate = c.String("reason-template")
 	opts.SHA1 = c.String("sha1")
 
 	vars, err := parseVarsFlag(c.StringSlice("var"))
@@ -69,6 +71,7 @@ func (runner *Runner) hideAction(c *cli.Context) error {
 
 	cfgReader := config.Reader{
 		ExistFile: existFile,
+		Stdin:     runner.Stdin,
 	}
 
 	cfg, err := cfgReader.FindAndRead(opts.ConfigPath, wd)
@@ -95,6 +98,12 @@ func (runner *Runner) hideAction(c *cli.Context) error {
 		Platform: pt,
 		Config:   cfg,
 		Expr:     &expr.Expr{},
+		Renderer: &template.Renderer{
+			Getenv:       os.Getenv,
+			GHEBaseURL:   cfg.GHEBaseURL,
+			Environ:      os.Environ,
+			EnvAllowlist: cfg.TemplateEnv,
+		},
 	}
 	return ctrl.Hide(c.Context, opts) //nolint:wrapcheck
 }
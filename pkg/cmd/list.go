@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/api"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/github-comment/pkg/platform"
+	"github.com/urfave/cli/v2"
+)
+
+// parseListOptions parses the command line arguments of the subcommand "list".
+func parseListOptions(opts *option.ListOptions, c *cli.Context) {
+	opts.Org = c.String("org")
+	opts.Repo = c.String("repo")
+	opts.Token = c.String("token")
+	opts.ConfigPath = c.String("config")
+	opts.PRNumber = c.Int("pr")
+	opts.OutputFormat = c.String("output-format")
+	opts.LogLevel = c.String("log-level")
+}
+
+// listAction is an entrypoint of the subcommand "list".
+func (runner *Runner) listAction(c *cli.Context) error {
+	opts := &option.ListOptions{}
+	parseListOptions(opts, c)
+
+	setLogLevel(opts.LogLevel)
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get a current directory path: %w", err)
+	}
+
+	cfgReader := config.Reader{
+		ExistFile: existFile,
+		Stdin:     runner.Stdin,
+	}
+
+	cfg, err := cfgReader.FindAndRead(opts.ConfigPath, wd)
+	if err != nil {
+		return fmt.Errorf("find and read a configuration file: %w", err)
+	}
+
+	var pt api.Platform = platform.Get()
+
+	gh, err := getGitHub(c.Context, &opts.Options, cfg)
+	if err != nil {
+		return fmt.Errorf("initialize commenter: %w", err)
+	}
+
+	ctrl := api.ListController{
+		Stdout:   runner.Stdout,
+		GitHub:   gh,
+		Platform: pt,
+		Config:   cfg,
+	}
+	return ctrl.List(c.Context, opts) //nolint:wrapcheck
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment/pkg/api"
 	"github.com/suzuki-shunsuke/github-comment/pkg/config"
 	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+	"github.com/suzuki-shunsuke/github-comment/pkg/gitlab"
 	"github.com/suzuki-shunsuke/github-comment/pkg/option"
 	"github.com/suzuki-shunsuke/github-comment/pkg/platform"
 	"github.com/suzuki-shunsuke/github-comment/pkg/template"
@@ -50,6 +54,36 @@ func parseVarFilesFlag(varsSlice []string) (map[string]string, error) {
 	return vars, nil
 }
 
+// parseCommaSeparated splits a comma-separated flag value. Empty elements are removed.
+func parseCommaSeparated(s string) []string {
+	var ret []string
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			ret = append(ret, elem)
+		}
+	}
+	return ret
+}
+
+// appendFooterFile appends the footer template read from the file to the configuration.
+// A relative path is resolved relative to the configuration file.
+func appendFooterFile(cfg *config.Config, p string) error {
+	if p == "" {
+		return nil
+	}
+	if !filepath.IsAbs(p) && cfg.Path != "" {
+		p = filepath.Join(filepath.Dir(cfg.Path), p)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("read a footer file %s: %w", p, err)
+	}
+	cfg.Footers = append(cfg.Footers, &config.Footer{
+		Template: string(b),
+	})
+	return nil
+}
+
 // parsePostOptions parses the command line arguments of the subcommand "post".
 func parsePostOptions(opts *option.PostOptions, c *cli.Context) error {
 	opts.Org = c.String("org")
@@ -58,14 +92,61 @@ func parsePostOptions(opts *option.PostOptions, c *cli.Context) error {
 	opts.SHA1 = c.String("sha1")
 	opts.Template = c.String("template")
 	opts.TemplateKey = c.String("template-key")
+	if p := c.String("template-file"); p != "" {
+		if c.IsSet("template") || c.IsSet("template-key") || c.Bool("stdin-template") {
+			return errors.New("template-file can't be used with template, template-key, or stdin-template")
+		}
+		tpl, err := readTemplateFile(p)
+		if err != nil {
+			return err
+		}
+		opts.Template = tpl
+	}
+	// the template key is derived from the branch only if --template-key isn't set
+	opts.TemplateKeyFromGit = c.Bool("comment-key-from-git") && !c.IsSet("template-key")
+	opts.Target = c.String("target")
+	opts.Lang = c.String("lang")
 	opts.ConfigPath = c.String("config")
 	opts.PRNumber = c.Int("pr")
 	opts.DryRun = c.Bool("dry-run")
+	opts.DryRunOutput = c.String("dry-run-output")
+	opts.DryRunOutputFormat = c.String("dry-run-output-format")
 	opts.SkipNoToken = c.Bool("skip-no-token")
 	opts.Silent = c.Bool("silent")
+	opts.RequirePR = c.Bool("require-pr")
+	opts.DebugFooter = c.Bool("debug-footer")
+	opts.MinimizeOnCreate = c.Bool("minimize-on-create")
+	opts.IdempotencyKey = c.String("idempotency-key")
+	opts.PRCommentLimit = c.Int("pr-comment-limit")
+	opts.PRBase = parseCommaSeparated(c.String("pr-base"))
+	opts.CooldownFile = c.String("comment-cooldown-file")
+	opts.FooterFile = c.String("comment-footer-file")
+	opts.EnvFile = c.String("env-file")
+	opts.Summary = c.String("summary")
+	opts.Cooldown = c.Duration("comment-cooldown")
+	opts.MatrixJSON = c.String("matrix-json")
 	opts.StdinTemplate = c.Bool("stdin-template")
 	opts.LogLevel = c.String("log-level")
 	opts.UpdateCondition = c.String("update-condition")
+	opts.Sticky = c.String("sticky")
+	opts.CommentIf = c.String("comment-if")
+	opts.Once = c.Bool("once")
+	opts.MergeVarsFromComment = c.Bool("merge-vars-from-comment")
+	opts.EditWithin = c.Duration("edit-within")
+	opts.Append = c.Bool("append")
+	opts.AppendDelimiter = c.String("append-delimiter")
+	opts.Repin = c.Bool("repin")
+	opts.RepinCooldown = c.Duration("repin-cooldown")
+	opts.NoMetadata = c.Bool("no-metadata")
+	opts.ValidateMentions = c.String("validate-mentions")
+	opts.MetadataOut = c.String("metadata-out")
+	if s := c.String("comment-id"); s != "" {
+		commentID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || commentID <= 0 {
+			return errors.New("comment-id must be a positive integer: " + s)
+		}
+		opts.CommentID = commentID
+	}
 	vars, err := parseVarsFlag(c.StringSlice("var"))
 	if err != nil {
 		return err
@@ -78,30 +159,135 @@ func parsePostOptions(opts *option.PostOptions, c *cli.Context) error {
 		vars[k] = v
 	}
 	opts.Vars = vars
+	structuredVars, err := parseStructuredVarFiles(c.StringSlice("var-file-json"), c.StringSlice("var-file-yaml"))
+	if err != nil {
+		return err
+	}
+	opts.StructuredVars = structuredVars
+	opts.AllowUndefinedVars = c.Bool("allow-undefined-vars")
 	return nil
 }
 
+// newRenderer returns a template renderer.
+// delims is the command line option --delims, which takes precedence over the configuration file.
+func newRenderer(cfg *config.Config, delims string, getenv func(string) string, environ []string) (*template.Renderer, error) {
+	renderer := &template.Renderer{
+		Getenv:     getenv,
+		GHEBaseURL: cfg.GHEBaseURL,
+		Environ: func() []string {
+			return environ
+		},
+		EnvAllowlist: cfg.TemplateEnv,
+	}
+	if delims == "" {
+		delims = cfg.Delims
+	}
+	if delims == "" {
+		return renderer, nil
+	}
+	left, right, err := template.ParseDelims(delims)
+	if err != nil {
+		return nil, fmt.Errorf("parse template delimiters: %w", err)
+	}
+	renderer.LeftDelim = left
+	renderer.RightDelim = right
+	return renderer, nil
+}
+
 func getGitHub(ctx context.Context, opts *option.Options, cfg *config.Config) (api.GitHub, error) {
+	opts.Token = getToken(opts.Token, cfg.Platform, os.Getenv)
 	if opts.DryRun {
 		return &github.Mock{
-			Stderr: os.Stderr,
-			Silent: opts.Silent,
+			Stderr:     os.Stderr,
+			Silent:     opts.Silent,
+			Output:     opts.DryRunOutput,
+			OutputJSON: opts.DryRunOutputFormat == option.DryRunOutputFormatJSON,
 		}, nil
 	}
-	if opts.SkipNoToken && opts.Token == "" {
+	if opts.SkipNoToken && opts.Token == "" && cfg.App == nil {
 		return &github.Mock{
 			Stderr: os.Stderr,
 			Silent: opts.Silent,
 		}, nil
 	}
 
+	maxAttempts, initialInterval, err := getRetryConfig(cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Platform {
+	case "", config.PlatformGitHub:
+	case config.PlatformGitLab:
+		if cfg.App != nil {
+			return nil, errors.New("app is supported only on GitHub")
+		}
+		var httpClient *http.Client
+		if maxAttempts > 1 {
+			httpClient = &http.Client{
+				Transport: github.NewRetryTransport(http.DefaultTransport, maxAttempts, initialInterval),
+			}
+		}
+		return gitlab.New(&gitlab.ParamNew{
+			Token:      opts.Token,
+			BaseURL:    cfg.GitLabBaseURL,
+			HTTPClient: httpClient,
+		}), nil
+	default:
+		return nil, errors.New(`platform must be either "github" or "gitlab"`)
+	}
+
+	var app *github.AppParam
+	if cfg.App != nil {
+		a, err := getAppParam(cfg)
+		if err != nil {
+			return nil, err
+		}
+		app = a
+		// the installation access token is minted lazily when GitHub API is called, so the token isn't required
+		opts.AppAuth = true
+	}
+
+	if cfg.ListCommentsConcurrency < 0 {
+		return nil, errors.New("list_comments_concurrency must not be negative")
+	}
 	return github.New(ctx, &github.ParamNew{ //nolint:wrapcheck
-		Token:              opts.Token,
-		GHEBaseURL:         cfg.GHEBaseURL,
-		GHEGraphQLEndpoint: cfg.GHEGraphQLEndpoint,
+		Token:                   opts.Token,
+		App:                     app,
+		GHEBaseURL:              cfg.GHEBaseURL,
+		GHEGraphQLEndpoint:      cfg.GHEGraphQLEndpoint,
+		GHEUploadURL:            cfg.GHEUploadURL,
+		GHEAPIPath:              cfg.GHEAPIPath,
+		ListCommentsConcurrency: cfg.ListCommentsConcurrency,
+		RetryMaxAttempts:        maxAttempts,
+		RetryInitialInterval:    initialInterval,
 	})
 }
 
+// getRetryConfig returns the max number of attempts and the initial interval of retries of API calls.
+// If they aren't configured, the default values are returned.
+func getRetryConfig(cfg *config.RetryConfig) (int, time.Duration, error) {
+	maxAttempts := github.DefaultRetryMaxAttempts
+	initialInterval := github.DefaultRetryInitialInterval
+	if cfg == nil {
+		return maxAttempts, initialInterval, nil
+	}
+	if cfg.MaxAttempts < 0 {
+		return 0, 0, errors.New("retry.max_attempts must not be negative")
+	}
+	if cfg.MaxAttempts != 0 {
+		maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialInterval != "" {
+		d, err := time.ParseDuration(cfg.InitialInterval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse retry.initial_interval as a duration: %w", err)
+		}
+		initialInterval = d
+	}
+	return maxAttempts, initialInterval, nil
+}
+
 func setLogLevel(logLevel string) {
 	if logLevel == "" {
 		return
@@ -137,16 +323,47 @@ func (runner *Runner) postAction(c *cli.Context) error {
 		return fmt.Errorf("get a current directory path: %w", err)
 	}
 
+	if c.Bool("stdin-json") {
+		if opts.ConfigPath == config.StdinPath || opts.StdinTemplate {
+			return errors.New("stdin-json can't be used with stdin-template or --config -")
+		}
+		if err := readStdinJSON(runner.Stdin, opts); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("stdin-vars") {
+		if opts.ConfigPath == config.StdinPath || opts.StdinTemplate || c.Bool("stdin-json") {
+			return errors.New("stdin-vars can't be used with stdin-json, stdin-template, or --config -")
+		}
+		if err := readStdinVars(runner.Stdin, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.ConfigPath == config.StdinPath && opts.StdinTemplate {
+		return errors.New("the configuration and the template can't be read from the standard input at the same time")
+	}
+
 	cfgReader := config.Reader{
 		ExistFile: existFile,
+		Stdin:     runner.Stdin,
 	}
 
 	cfg, err := cfgReader.FindAndRead(opts.ConfigPath, wd)
 	if err != nil {
 		return fmt.Errorf("find and read a configuration file: %w", err)
 	}
+	if err := appendFooterFile(cfg, opts.FooterFile); err != nil {
+		return err
+	}
 	opts.SkipNoToken = opts.SkipNoToken || cfg.SkipNoToken
 
+	getenv, environ, err := loadEnvFile(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("load environment variables from the env file: %w", err)
+	}
+
 	var pt api.Platform = platform.Get()
 
 	gh, err := getGitHub(c.Context, &opts.Options, cfg)
@@ -154,20 +371,25 @@ func (runner *Runner) postAction(c *cli.Context) error {
 		return fmt.Errorf("initialize commenter: %w", err)
 	}
 
+	renderer, err := newRenderer(cfg, c.String("delims"), getenv, environ)
+	if err != nil {
+		return err
+	}
+
 	ctrl := api.PostController{
 		Wd:     wd,
-		Getenv: os.Getenv,
+		Getenv: getenv,
 		HasStdin: func() bool {
 			return !term.IsTerminal(0)
 		},
-		Stdin:  runner.Stdin,
-		Stderr: runner.Stderr,
-		GitHub: gh,
-		Renderer: &template.Renderer{
-			Getenv: os.Getenv,
-		},
+		Stdin:    runner.Stdin,
+		Stdout:   runner.Stdout,
+		Stderr:   runner.Stderr,
+		GitHub:   gh,
+		Renderer: renderer,
 		Platform: pt,
 		Config:   cfg,
+		Version:  runner.LDFlags.Version,
 		Expr:     &expr.Expr{},
 	}
 	return ctrl.Post(c.Context, opts) //nolint:wrapcheck
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/api"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+	"github.com/suzuki-shunsuke/github-comment/pkg/platform"
+	"github.com/urfave/cli/v2"
+)
+
+// parseReactOptions parses the command line arguments of the subcommand "react".
+func parseReactOptions(opts *option.ReactOptions, c *cli.Context) {
+	opts.Org = c.String("org")
+	opts.Repo = c.String("repo")
+	opts.Token = c.String("token")
+	opts.SHA1 = c.String("sha1")
+	opts.ConfigPath = c.String("config")
+	opts.PRNumber = c.Int("pr")
+	opts.TemplateKey = c.String("template-key")
+	opts.Content = c.String("content")
+	opts.UpdateCondition = c.String("update-condition")
+	opts.DryRun = c.Bool("dry-run")
+	opts.SkipNoToken = c.Bool("skip-no-token")
+	opts.Silent = c.Bool("silent")
+	opts.LogLevel = c.String("log-level")
+}
+
+// reactAction is an entrypoint of the subcommand "react".
+func (runner *Runner) reactAction(c *cli.Context) error {
+	if a := os.Getenv("GITHUB_COMMENT_SKIP"); a != "" {
+		skipComment, err := strconv.ParseBool(a)
+		if err != nil {
+			return fmt.Errorf("parse the environment variable GITHUB_COMMENT_SKIP as a bool: %w", err)
+		}
+		if skipComment {
+			return nil
+		}
+	}
+	opts := &option.ReactOptions{}
+	parseReactOptions(opts, c)
+
+	setLogLevel(opts.LogLevel)
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get a current directory path: %w", err)
+	}
+
+	cfgReader := config.Reader{
+		ExistFile: existFile,
+		Stdin:     runner.Stdin,
+	}
+
+	cfg, err := cfgReader.FindAndRead(opts.ConfigPath, wd)
+	if err != nil {
+		return fmt.Errorf("find and read a configuration file: %w", err)
+	}
+	opts.SkipNoToken = opts.SkipNoToken || cfg.SkipNoToken
+
+	var pt api.Platform = platform.Get()
+
+	// In case of dry-run, comments are listed actually but the reaction isn't added.
+	ghOpts := opts.Options
+	ghOpts.DryRun = false
+	gh, err := getGitHub(c.Context, &ghOpts, cfg)
+	if err != nil {
+		return fmt.Errorf("initialize commenter: %w", err)
+	}
+	opts.Token = ghOpts.Token
+	opts.AppAuth = ghOpts.AppAuth
+
+	ctrl := api.ReactController{
+		Stderr:   runner.Stderr,
+		GitHub:   gh,
+		Platform: pt,
+		Config:   cfg,
+		Expr:     &expr.Expr{},
+	}
+	return ctrl.React(c.Context, opts) //nolint:wrapcheck
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// configSchemaAction is an entrypoint of the subcommand "config-schema".
+func (runner *Runner) configSchemaAction(c *cli.Context) error {
+	encoder := json.NewEncoder(runner.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config.Schema()); err != nil {
+		return fmt.Errorf("output the JSON Schema of the configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+// postInput is a JSON document which parameterizes the subcommand "post".
+type postInput struct {
+	Org             string            `json:"org"`
+	Repo            string            `json:"repo"`
+	SHA1            string            `json:"sha1"`
+	PR              int               `json:"pr"`
+	Template        string            `json:"template"`
+	TemplateKey     string            `json:"template_key"`
+	Target          string            `json:"target"`
+	UpdateCondition string            `json:"update_condition"`
+	CommentIf       string            `json:"comment_if"`
+	IdempotencyKey  string            `json:"idempotency_key"`
+	Vars            map[string]string `json:"vars"`
+}
+
+// readStdinJSON reads a JSON document from r and merges it into opts.
+// Non empty fields of the JSON document take precedence over command line options.
+// Unknown fields are rejected to report typos.
+func readStdinJSON(r io.Reader, opts *option.PostOptions) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	input := &postInput{}
+	if err := decoder.Decode(input); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("the JSON document from the standard input is empty")
+		}
+		return fmt.Errorf("parse the standard input as JSON: %w", err)
+	}
+	for _, a := range []struct {
+		dest *string
+		src  string
+	}{
+		{&opts.Org, input.Org},
+		{&opts.Repo, input.Repo},
+		{&opts.SHA1, input.SHA1},
+		{&opts.Template, input.Template},
+		{&opts.TemplateKey, input.TemplateKey},
+		{&opts.Target, input.Target},
+		{&opts.UpdateCondition, input.UpdateCondition},
+		{&opts.CommentIf, input.CommentIf},
+		{&opts.IdempotencyKey, input.IdempotencyKey},
+	} {
+		if a.src != "" {
+			*a.dest = a.src
+		}
+	}
+	if input.PR > 0 {
+		opts.PRNumber = input.PR
+	}
+	if opts.Vars == nil {
+		opts.Vars = make(map[string]string, len(input.Vars))
+	}
+	for k, v := range input.Vars {
+		opts.Vars[k] = v
+	}
+	return nil
+}
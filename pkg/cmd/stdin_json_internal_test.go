@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func Test_readStdinJSON(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		input string
+		opts  *option.PostOptions
+		exp   *option.PostOptions
+		isErr bool
+	}{
+		{
+			title: "merge",
+			input: `{"template_key": "hello", "pr": 3, "vars": {"foo": "bar"}}`,
+			opts: &option.PostOptions{
+				Options: option.Options{
+					Org:         "suzuki-shunsuke",
+					TemplateKey: "default",
+					Vars: map[string]string{
+						"zoo": "yoo",
+					},
+				},
+			},
+			exp: &option.PostOptions{
+				Options: option.Options{
+					Org:         "suzuki-shunsuke",
+					TemplateKey: "hello",
+					PRNumber:    3,
+					Vars: map[string]string{
+						"zoo": "yoo",
+						"foo": "bar",
+					},
+				},
+			},
+		},
+		{
+			title: "unknown field",
+			input: `{"template-key": "hello"}`,
+			opts:  &option.PostOptions{},
+			isErr: true,
+		},
+		{
+			title: "empty",
+			opts:  &option.PostOptions{},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := readStdinJSON(strings.NewReader(d.input), d.opts)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, d.opts)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/envfile"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+// readStdinVars reads KEY=VALUE lines from r and merges them into opts.Vars.
+// Lines are parsed per dotenv conventions same as --env-file.
+// Variables from the standard input take precedence over --var and --var-file.
+func readStdinVars(r io.Reader, opts *option.PostOptions) error {
+	vars, err := envfile.Parse(r)
+	if err != nil {
+		return fmt.Errorf("parse variables from the standard input: %w", err)
+	}
+	if opts.Vars == nil {
+		opts.Vars = make(map[string]string, len(vars))
+	}
+	for k, v := range vars {
+		opts.Vars[k] = v
+	}
+	return nil
+}
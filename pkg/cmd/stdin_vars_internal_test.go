@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/option"
+)
+
+func Test_readStdinVars(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		input string
+		opts  *option.PostOptions
+		exp   map[string]string
+		isErr bool
+	}{
+		{
+			title: "merge",
+			input: "# generated\nfoo=bar\n\nzoo=\"hello world\"\n",
+			opts: &option.PostOptions{
+				Options: option.Options{
+					Vars: map[string]string{
+						"zoo": "yoo",
+						"a":   "b",
+					},
+				},
+			},
+			exp: map[string]string{
+				"foo": "bar",
+				"zoo": "hello world",
+				"a":   "b",
+			},
+		},
+		{
+			title: "vars are nil",
+			input: "foo=bar",
+			opts:  &option.PostOptions{},
+			exp: map[string]string{
+				"foo": "bar",
+			},
+		},
+		{
+			title: "invalid line",
+			input: "foo",
+			opts:  &option.PostOptions{},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := readStdinVars(strings.NewReader(d.input), d.opts)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, d.opts.Vars)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// readTemplateFile reads the comment template from the file given by --template-file.
+func readTemplateFile(p string) (string, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("the template file %s isn't found", p)
+		}
+		return "", fmt.Errorf("read the template file %s: %w", p, err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readTemplateFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	p := filepath.Join(dir, "comment.md")
+	require.Nil(t, os.WriteFile(p, []byte("{{.JoinCommand}}\n"), 0o600))
+	data := []struct {
+		title string
+		path  string
+		exp   string
+		isErr bool
+	}{
+		{
+			title: "normal",
+			path:  p,
+			exp:   "{{.JoinCommand}}\n",
+		},
+		{
+			title: "not found",
+			path:  filepath.Join(dir, "foo.md"),
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			tpl, err := readTemplateFile(d.path)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, tpl)
+		})
+	}
+}
+
+func TestRunner_Run_templateFile(t *testing.T) {
+	t.Parallel()
+	p := filepath.Join(t.TempDir(), "comment.md")
+	require.Nil(t, os.WriteFile(p, []byte("hello"), 0o600))
+	data := []struct {
+		title string
+		args  []string
+	}{
+		{
+			title: "post with template",
+			args:  []string{"post", "--template-file", p, "--template", "hello"},
+		},
+		{
+			title: "post with template-key",
+			args:  []string{"post", "--template-file", p, "--template-key", "default"},
+		},
+		{
+			title: "post with stdin-template",
+			args:  []string{"post", "--template-file", p, "--stdin-template"},
+		},
+		{
+			title: "exec with template",
+			args:  []string{"exec", "--template-file", p, "--template", "hello", "--", "true"},
+		},
+		{
+			title: "exec with template-key",
+			args:  []string{"exec", "--template-file", p, "--template-key", "default", "--", "true"},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			runner := &Runner{
+				Stdin:   strings.NewReader(""),
+				Stdout:  io.Discard,
+				Stderr:  io.Discard,
+				LDFlags: &LDFlags{},
+			}
+			err := runner.Run(context.Background(), append([]string{"github-comment"}, d.args...))
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "template-file can't be used with")
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/api"
+	"github.com/suzuki-shunsuke/github-comment/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// templatesAction is an entrypoint of the subcommand "templates".
+func (runner *Runner) templatesAction(c *cli.Context) error {
+	setLogLevel(c.String("log-level"))
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get a current directory path: %w", err)
+	}
+
+	cfgReader := config.Reader{
+		ExistFile: existFile,
+		Stdin:     runner.Stdin,
+	}
+
+	cfg, err := cfgReader.FindAndRead(c.String("config"), wd)
+	if err != nil {
+		return fmt.Errorf("find and read a configuration file: %w", err)
+	}
+
+	ctrl := api.TemplatesController{
+		Stdout: runner.Stdout,
+		Config: cfg,
+	}
+	return ctrl.List(c.Context, c.String("output-format")) //nolint:wrapcheck
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import "github.com/suzuki-shunsuke/github-comment/pkg/config"
+
+// getToken returns the API token.
+// If the token isn't given by the command line option, it's read from environment variables.
+// In case of GitLab, GITLAB_TOKEN takes precedence over GITHUB_TOKEN and GITHUB_ACCESS_TOKEN,
+// because GITHUB_TOKEN may be set for other tools in GitLab CI.
+func getToken(token, platform string, getenv func(string) string) string {
+	if token != "" {
+		return token
+	}
+	envs := []string{"GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN"}
+	if platform == config.PlatformGitLab {
+		envs = []string{"GITLAB_TOKEN", "GITHUB_TOKEN", "GITHUB_ACCESS_TOKEN"}
+	}
+	for _, env := range envs {
+		if v := getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getToken(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		token    string
+		platform string
+		env      map[string]string
+		exp      string
+	}{
+		{
+			title: "command line option takes precedence",
+			token: "foo",
+			env: map[string]string{
+				"GITHUB_TOKEN": "bar",
+			},
+			exp: "foo",
+		},
+		{
+			title: "GITHUB_TOKEN",
+			env: map[string]string{
+				"GITHUB_TOKEN":        "bar",
+				"GITHUB_ACCESS_TOKEN": "baz",
+				"GITLAB_TOKEN":        "qux",
+			},
+			exp: "bar",
+		},
+		{
+			title: "GITHUB_ACCESS_TOKEN",
+			env: map[string]string{
+				"GITHUB_ACCESS_TOKEN": "baz",
+				"GITLAB_TOKEN":        "qux",
+			},
+			exp: "baz",
+		},
+		{
+			title: "GITLAB_TOKEN isn't used on GitHub",
+			env: map[string]string{
+				"GITLAB_TOKEN": "qux",
+			},
+			exp: "",
+		},
+		{
+			title:    "GITLAB_TOKEN takes precedence on GitLab",
+			platform: "gitlab",
+			env: map[string]string{
+				"GITHUB_TOKEN": "bar",
+				"GITLAB_TOKEN": "qux",
+			},
+			exp: "qux",
+		},
+		{
+			title:    "fallback to GITHUB_TOKEN on GitLab",
+			platform: "gitlab",
+			env: map[string]string{
+				"GITHUB_TOKEN": "bar",
+			},
+			exp: "bar",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			token := getToken(d.token, d.platform, func(k string) string {
+				return d.env[k]
+			})
+			require.Equal(t, d.exp, token)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// parseStructuredVarFiles reads JSON and YAML files and merges their top-level keys into a map.
+// Each file must be an object. If keys are duplicated, the later file takes precedence and YAML files take precedence over JSON files.
+func parseStructuredVarFiles(jsonPaths, yamlPaths []string) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	for _, p := range jsonPaths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read a var file %s: %w", p, err)
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("parse a var file %s as JSON. The file must be a JSON object: %w", p, err)
+		}
+		for k, v := range m {
+			vars[k] = v
+		}
+	}
+	for _, p := range yamlPaths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read a var file %s: %w", p, err)
+		}
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("parse a var file %s as YAML. The file must be a YAML mapping: %w", p, err)
+		}
+		for k, v := range m {
+			vars[k] = normalizeYAML(v)
+		}
+	}
+	return vars, nil
+}
+
+// normalizeYAML converts map[interface{}]interface{} decoded by yaml.v2 to map[string]interface{} recursively
+// so that nested keys can be referred as .Vars.foo.bar in templates.
+func normalizeYAML(v interface{}) interface{} {
+	switch a := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(a))
+		for k, v := range a {
+			m[fmt.Sprint(k)] = normalizeYAML(v)
+		}
+		return m
+	case map[string]interface{}:
+		for k, v := range a {
+			a[k] = normalizeYAML(v)
+		}
+		return a
+	case []interface{}:
+		for i, v := range a {
+			a[i] = normalizeYAML(v)
+		}
+		return a
+	default:
+		return v
+	}
+}
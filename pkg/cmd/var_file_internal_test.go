@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizeYAML(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		v     interface{}
+		exp   interface{}
+	}{
+		{
+			title: "scalar",
+			v:     "foo",
+			exp:   "foo",
+		},
+		{
+			title: "nested map",
+			v: map[interface{}]interface{}{
+				"foo": map[interface{}]interface{}{
+					"bar": 1,
+				},
+				"list": []interface{}{
+					map[interface{}]interface{}{
+						true: "yes",
+					},
+				},
+			},
+			exp: map[string]interface{}{
+				"foo": map[string]interface{}{
+					"bar": 1,
+				},
+				"list": []interface{}{
+					map[string]interface{}{
+						"true": "yes",
+					},
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, normalizeYAML(d.v))
+		})
+	}
+}
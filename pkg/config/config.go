@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -19,6 +21,110 @@ type Config struct {
 	Hide               map[string]string
 	SkipNoToken        bool `yaml:"skip_no_token"`
 	Silent             bool
+	Footers            []*Footer
+	// GHEUploadURL is the upload URL of GitHub Enterprise Server. If it's empty, it's derived from GHEBaseURL
+	GHEUploadURL string `yaml:"ghe_upload_url"`
+	// GHEAPIPath is the path of REST API of GitHub Enterprise Server. The default is "api/v3".
+	// If the API is served on GHEBaseURL itself, set "/"
+	GHEAPIPath string `yaml:"ghe_api_path"`
+	// Platform is the platform where comments are posted. The default is github.
+	// In case of gitlab, comments are posted to merge requests
+	Platform string `jsonschema:"enum=github|gitlab"`
+	// GitLabBaseURL is the base URL of GitLab REST API v4 such as https://gitlab.example.com/api/v4.
+	// The default is https://gitlab.com/api/v4
+	GitLabBaseURL string `yaml:"gitlab_base_url"`
+	// AutoTarget derives the comment target from GITHUB_JOB and the matrix context if neither --target nor --var target is set.
+	// The comment with the same target is updated by default.
+	AutoTarget bool `yaml:"auto_target"`
+	// UpdateKey is the name of the comment shared by all jobs.
+	// In case of post, the comment with the same update key and template key is updated instead of the comment with the same target.
+	UpdateKey string `yaml:"update_key"`
+	// DefaultUpdateCondition is the update condition of post, exec, and react which is used
+	// if neither the command line option nor the post or exec config of the template key sets the update condition.
+	// In case of post, sticky, update_key, and auto_target take precedence over it
+	DefaultUpdateCondition string `yaml:"default_update_condition"`
+	// Lang is the language of built-in templates such as "link". The default is English
+	Lang string `jsonschema:"enum=en|ja"`
+	// RedactPatterns are regular expressions applied to the rendered comment body before it's posted.
+	// Matched strings are replaced with "***"
+	RedactPatterns []string `yaml:"redact_patterns"`
+	// TemplateEnv is an allowlist of environment variable names which are returned by the template functions env and envPrefix.
+	// A name ending with "*" matches names with the prefix, e.g. "CI_*".
+	// If it's empty, env and envPrefix return an empty map.
+	// Don't allow environment variables which have secrets because they may be leaked to comments
+	TemplateEnv []string `yaml:"template_env"`
+	// NoEmbed is a list of variable names which are never embedded in the metadata
+	// even if they are included in embedded_var_names. They can be still used in templates
+	NoEmbed []string `yaml:"no_embed"`
+	// MaxCommentsPerPR is the max number of comments which github-comment posts to a pull request.
+	// New comments beyond the limit are refused. The default is 100
+	MaxCommentsPerPR int `yaml:"max_comments_per_pr"`
+	// DefaultExec configures the built-in default exec config, which is used if exec.default isn't configured
+	DefaultExec *DefaultExecConfig `yaml:"default_exec"`
+	// AuthorAssociations narrows comments which are updated or hidden to ones whose author associations are included.
+	// e.g. MEMBER, OWNER. If it's empty, comments aren't filtered by author associations
+	AuthorAssociations []string `yaml:"author_associations"`
+	// TemplateKeyRules derive the template key from the branch if --comment-key-from-git is set and --template-key isn't set.
+	// The first matched rule is used
+	TemplateKeyRules []*TemplateKeyRule `yaml:"template_key_rules"`
+	// Delims is a pair of space-separated delimiters of comment templates and templates in the field templates such as "<< >>".
+	// This is useful if comments include Go templates. The command line option --delims takes precedence
+	Delims string
+	// MetadataSchema declares types of embedded variables. The key is the variable name.
+	// Embedded variables are validated against the schema, and they are converted to the declared types
+	// when the metadata is read in update and hide conditions
+	MetadataSchema map[string]string `yaml:"metadata_schema" jsonschema:"enum=string|number|boolean"`
+	// Retry configures retries of GitHub and GitLab API calls on 5xx errors and rate limits
+	Retry *RetryConfig
+	// ListCommentsConcurrency is the number of pages of comments which are fetched concurrently.
+	// The default is 1, which means pages are fetched sequentially, and the max is 10
+	ListCommentsConcurrency int `yaml:"list_comments_concurrency"`
+	// App is credentials of a GitHub App. If it's set, github-comment authenticates as the GitHub App installation instead of the token
+	App *AppConfig
+	// Path is the path of the configuration file. It's empty if no configuration file is read
+	Path string `yaml:"-"`
+}
+
+// Footer is appended to comments.
+// If When is empty, the footer is always appended.
+// Otherwise the footer is appended only when When is evaluated as true.
+type Footer struct {
+	When     string
+	Template string
+}
+
+// DefaultExecConfig configures how the built-in default exec template renders the combined output.
+type DefaultExecConfig struct {
+	// MaxOutputSize is the max size of the combined output in bytes. If it's zero, the output isn't trimmed
+	MaxOutputSize int `yaml:"max_output_size"`
+	// OutputKeep is which part of the combined output is kept when the output is trimmed.
+	// tail (default): keep the end of the output
+	// head: keep the beginning of the output
+	OutputKeep string `yaml:"output_keep" jsonschema:"enum=head|tail"`
+}
+
+// RetryConfig configures retries of GitHub and GitLab API calls.
+type RetryConfig struct {
+	// MaxAttempts is the max number of attempts of an API call. The default is 3. If it's 1, API calls aren't retried
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialInterval is the interval before the first retry such as "1s". The default is "1s".
+	// The interval is doubled per retry
+	InitialInterval string `yaml:"initial_interval"`
+}
+
+// AppConfig is credentials of a GitHub App installation.
+// The private key path is relative to the configuration file.
+type AppConfig struct {
+	AppID          int64  `yaml:"app_id"`
+	InstallationID int64  `yaml:"installation_id"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+}
+
+// TemplateKeyRule maps a branch to a template key.
+type TemplateKeyRule struct {
+	// Branch is a regular expression of the branch name
+	Branch      string
+	TemplateKey string `yaml:"template_key"`
 }
 
 type Base struct {
@@ -26,14 +132,23 @@ type Base struct {
 	Repo string
 }
 
+// PostConfig is unmarshaled by UnmarshalYAML.
+// The yaml tags are used to generate the JSON Schema.
 type PostConfig struct {
 	Template           string
-	TemplateForTooLong string
-	EmbeddedVarNames   []string
+	TemplateForTooLong string   `yaml:"template_for_too_long"`
+	EmbeddedVarNames   []string `yaml:"embedded_var_names"`
 	// UpdateCondition Update the comment that matches with the condition.
 	// If multiple comments match, the latest comment is updated
 	// If no comment matches, aa new comment is created
-	UpdateCondition string
+	UpdateCondition string `yaml:"update"`
+	// MinInterval is a duration such as "1m".
+	// If the comment which matches with the update condition was updated within the duration, no comment is posted.
+	// This debounces comments posted repeatedly in a retry loop
+	MinInterval string `yaml:"min_interval"`
+	// Metadata is extra metadata embedded in the comment. Values are rendered as templates.
+	// Update and hide conditions can refer to them as Comment.Meta.<key>
+	Metadata map[string]string
 }
 
 func (pc *PostConfig) UnmarshalYAML(unmarshal func(interface{}) error) error { //nolint:cyclop
@@ -82,25 +197,146 @@ func (pc *PostConfig) UnmarshalYAML(unmarshal func(interface{}) error) error { /
 			}
 			pc.UpdateCondition = t
 		}
+		if tpl, ok := m["min_interval"]; ok {
+			t, ok := tpl.(string)
+			if !ok {
+				return fmt.Errorf("invalid config. min_interval should be string: %+v", tpl)
+			}
+			pc.MinInterval = t
+		}
+		if tpl, ok := m["metadata"]; ok {
+			t, ok := tpl.(map[interface{}]interface{})
+			if !ok {
+				return fmt.Errorf("invalid config. metadata should be map[string]string: %+v", tpl)
+			}
+			meta := make(map[string]string, len(t))
+			for k, v := range t {
+				key, ok := k.(string)
+				if !ok {
+					return fmt.Errorf("invalid config. metadata's key should be string: %+v", k)
+				}
+				s, ok := v.(string)
+				if !ok {
+					return fmt.Errorf("invalid config. metadata.%s should be string: %+v", key, v)
+				}
+				meta[key] = s
+			}
+			pc.Metadata = meta
+		}
 		return nil
 	}
 	return fmt.Errorf("invalid config. post config should be string or map[string]intterface{}: %+v", val)
 }
 
 type ExecConfig struct {
+	// Name is the name of the config. It's exposed as .MatchedConfigs in templates.
+	// If it's empty, When is used instead
+	Name               string
 	When               string
 	Template           string
 	TemplateForTooLong string   `yaml:"template_for_too_long"`
 	DontComment        bool     `yaml:"dont_comment"`
 	EmbeddedVarNames   []string `yaml:"embedded_var_names"`
+	// TooLongStrategy is how to handle the comment whose body is too long.
+	// fallback (default): use TemplateForTooLong
+	// truncate_middle: remove the middle of the body
+	// truncate_tail: remove the tail of the body
+	TooLongStrategy string `yaml:"too_long_strategy" jsonschema:"enum=fallback|truncate_middle|truncate_tail"`
+	// Status is a commit status which is set after the command is run
+	Status *StatusConfig
+	// IncludeStdout, IncludeStderr, and IncludeCombined control whether outputs are passed to templates.
+	// If they are false, the outputs are blanked before rendering templates. The default is true
+	IncludeStdout   *bool `yaml:"include_stdout"`
+	IncludeStderr   *bool `yaml:"include_stderr"`
+	IncludeCombined *bool `yaml:"include_combined"`
+	// ExitCodeFromOutput is a regular expression to extract the exit code from the combined output.
+	// The first capture group is parsed as the exit code.
+	// If the output matches, the extracted exit code takes precedence over the process exit code
+	// when When, the template, and the status are evaluated.
+	// The exit code of github-comment itself isn't changed.
+	ExitCodeFromOutput string `yaml:"exit_code_from_output"`
+	// FailIfOutputMatches is a regular expression.
+	// If the combined output matches and the exit code is 0, the exit code is treated as 1.
+	// This is useful when a wrapper such as make masks the exit code of the underlying command.
+	// It's evaluated after ExitCodeFromOutput.
+	FailIfOutputMatches string `yaml:"fail_if_output_matches"`
+	// PreCommentCommand is a shell command which is run after the template is rendered and before the comment is posted.
+	// The rendered body is passed via the standard input.
+	// The standard output must be a JSON object, which is merged into .Vars and the template is rendered again.
+	// If the command fails, the original body is posted
+	PreCommentCommand string `yaml:"pre_comment_command"`
+	// IncludeEnv is an allowlist of environment variable names which are exposed as .Command.Env in templates.
+	// Environment variables which aren't included in the list are never exposed to avoid leaking secrets
+	IncludeEnv []string `yaml:"include_env"`
+	// TruncateOutput is the output field which is truncated if the comment is too long.
+	// The output is truncated to fit the length remaining after the rest of the template.
+	// largest: the largest output among Stdout, Stderr, and CombinedOutput
+	// Stdout, Stderr, CombinedOutput: the specified output
+	TruncateOutput string `yaml:"truncate_output" jsonschema:"enum=largest|Stdout|Stderr|CombinedOutput"`
+	// MaxCombinedOutputSize is the max size of the combined output in bytes which is passed to templates.
+	// If it's zero, the output isn't trimmed
+	MaxCombinedOutputSize int `yaml:"max_combined_output_size"`
+	// CombinedOutputKeep is which part of the combined output is kept when the output is trimmed.
+	// tail (default): keep the end of the output
+	// head: keep the beginning of the output
+	CombinedOutputKeep string `yaml:"combined_output_keep" jsonschema:"enum=head|tail"`
+	// Path and Line are rendered as templates.
+	// If Path is set, the comment is posted as a review comment anchored to the line of the file in the pull request diff.
+	// Line is required if Path is set
+	Path string
+	Line string
+	// Metadata is extra metadata embedded in the comment. Values are rendered as templates.
+	// Update and hide conditions can refer to them as Comment.Meta.<key>
+	Metadata map[string]string
+	// UpdateCondition updates the comment that matches with the condition instead of creating a new comment.
+	// It takes precedence over the command line option --update-condition and default_update_condition
+	UpdateCondition string `yaml:"update"`
+}
+
+// StatusConfig is a commit status.
+// Description and TargetURL are rendered as templates.
+type StatusConfig struct {
+	Context     string
+	Description string
+	TargetURL   string `yaml:"target_url"`
+	// States maps exit codes to states of the commit status.
+	// If the exit code isn't found, the state is "success" if the exit code is 0, otherwise "failure"
+	States map[int]string `jsonschema:"enum=error|failure|pending|success"`
 }
 
+const (
+	PlatformGitHub = "github"
+	PlatformGitLab = "gitlab"
+)
+
+const (
+	TooLongStrategyFallback       = "fallback"
+	TooLongStrategyTruncateMiddle = "truncate_middle"
+	TooLongStrategyTruncateTail   = "truncate_tail"
+)
+
+const (
+	MetadataTypeString  = "string"
+	MetadataTypeNumber  = "number"
+	MetadataTypeBoolean = "boolean"
+)
+
+const (
+	OutputKeepHead = "head"
+	OutputKeepTail = "tail"
+)
+
 type ExistFile func(string) bool
 
 type Reader struct {
 	ExistFile ExistFile
+	// Stdin is used to read the configuration if the configuration file path is "-"
+	Stdin io.Reader
 }
 
+// StdinPath is the configuration file path to read the configuration from the standard input.
+const StdinPath = "-"
+
 func (reader *Reader) find(wd string) (string, bool) {
 	names := []string{"github-comment.yaml", "github-comment.yml", ".github-comment.yml", ".github-comment.yaml"}
 	for {
@@ -117,7 +353,24 @@ func (reader *Reader) find(wd string) (string, bool) {
 	}
 }
 
+func (reader *Reader) readStdin() (*Config, error) {
+	if reader.Stdin == nil {
+		return nil, errors.New("the standard input isn't available to read the configuration")
+	}
+	cfg := &Config{}
+	if err := yaml.NewDecoder(reader.Stdin).Decode(cfg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the configuration from the standard input is empty")
+		}
+		return nil, fmt.Errorf("decode a configuration from the standard input as YAML: %w", err)
+	}
+	return cfg, nil
+}
+
 func (reader *Reader) read(p string) (*Config, error) {
+	if p == StdinPath {
+		return reader.readStdin()
+	}
 	f, err := os.Open(p)
 	if err != nil {
 		return nil, fmt.Errorf("open a configuration file "+p+": %w", err)
@@ -149,6 +402,9 @@ func (reader *Reader) FindAndRead(cfgPath, wd string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfgPath != StdinPath {
+		cfg.Path = cfgPath
+	}
 	if cfg.Hide == nil {
 		cfg.Hide = map[string]string{
 			"default": defaultHideCondition,
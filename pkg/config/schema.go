@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema returns the JSON Schema of the configuration file.
+// The schema is derived from the struct tags of Config.
+// The property name is the yaml tag or the lower case field name, which is the same as yaml.v2.
+// The enum is given by the jsonschema tag such as `jsonschema:"enum=foo|bar"`.
+func Schema() map[string]interface{} {
+	schema := schemaOf(reflect.TypeOf(Config{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "github-comment configuration"
+	return schema
+}
+
+var durationType = reflect.TypeOf(time.Duration(0)) //nolint:gochecknoglobals
+
+func schemaOf(t reflect.Type) map[string]interface{} { //nolint:cyclop
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(PostConfig{}) {
+		// PostConfig can be either a template string or an object
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				structSchema(t),
+			},
+		}
+	}
+	if t == durationType {
+		return map[string]interface{}{"type": "string"}
+	}
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaOf(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaOf(t.Elem()),
+		}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		// interface{} accepts any value
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		if tag := field.Tag.Get("yaml"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		if name == "-" {
+			continue
+		}
+		prop := schemaOf(field.Type)
+		if enum := parseSchemaEnum(field.Tag.Get("jsonschema")); enum != nil {
+			// the enum of a map restricts values of the map
+			if field.Type.Kind() == reflect.Map {
+				prop["additionalProperties"].(map[string]interface{})["enum"] = enum //nolint:forcetypeassert
+			} else {
+				prop["enum"] = enum
+			}
+		}
+		props[name] = prop
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+func parseSchemaEnum(tag string) []string {
+	for _, elem := range strings.Split(tag, ",") {
+		if a := strings.TrimPrefix(elem, "enum="); a != elem {
+			return strings.Split(a, "|")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal(Schema())
+	require.Nil(t, err)
+	schema := map[string]interface{}{}
+	require.Nil(t, json.Unmarshal(b, &schema))
+	props, ok := schema["properties"].(map[string]interface{})
+	require.True(t, ok)
+	for _, name := range []string{"base", "ghe_base_url", "vars", "templates", "post", "exec", "hide", "skip_no_token", "auto_target"} {
+		_, ok := props[name]
+		require.True(t, ok, name)
+	}
+}
@@ -0,0 +1,116 @@
+// Package envfile parses dotenv files.
+package envfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`) //nolint:gochecknoglobals
+
+// Read reads a dotenv file.
+func Read(p string) (map[string]string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("open an env file %s: %w", p, err)
+	}
+	defer f.Close()
+	envs, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse an env file %s: %w", p, err)
+	}
+	return envs, nil
+}
+
+// Parse parses KEY=VALUE pairs per dotenv conventions.
+// Empty lines and lines starting with "#" are ignored, and the prefix "export " is allowed.
+// A value can be quoted with double quotes or single quotes.
+// Escape sequences such as \n are interpreted only in double quoted values.
+// A "#" preceded by a space starts a comment in unquoted values.
+func Parse(r io.Reader) (map[string]string, error) {
+	envs := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		envs[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read an env file: %w", err)
+	}
+	return envs, nil
+}
+
+func parseLine(line string) (string, string, error) {
+	line = strings.TrimPrefix(line, "export ")
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", fmt.Errorf("the format must be KEY=VALUE: %s", line)
+	}
+	key = strings.TrimSpace(key)
+	if !keyPattern.MatchString(key) {
+		return "", "", fmt.Errorf("invalid key: %s", key)
+	}
+	value, err := parseValue(strings.TrimSpace(value))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	return key, value, nil
+}
+
+func parseValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := closingQuote(value, quote)
+		if end == -1 {
+			return "", fmt.Errorf("the quote %c isn't closed", quote)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected characters after the closing quote: %s", rest)
+		}
+		if quote == '\'' {
+			return value[1:end], nil
+		}
+		s, err := strconv.Unquote(value[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("unquote a double quoted value: %w", err)
+		}
+		return s, nil
+	}
+	if idx := strings.Index(value, " #"); idx != -1 {
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// closingQuote returns the index of the closing quote. If the quote isn't closed, -1 is returned.
+// In double quoted values, an escaped double quote doesn't close the value.
+func closingQuote(value string, quote byte) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
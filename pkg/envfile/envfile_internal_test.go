@@ -0,0 +1,79 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		input string
+		exp   map[string]string
+		isErr bool
+	}{
+		{
+			title: "normal",
+			input: `# comment
+
+FOO=foo
+export BAR = bar # comment
+EMPTY=
+DOUBLE="hello\nworld # not comment"
+SINGLE='hello\n'
+URL=https://example.com/#anchor
+`,
+			exp: map[string]string{
+				"FOO":    "foo",
+				"BAR":    "bar",
+				"EMPTY":  "",
+				"DOUBLE": "hello\nworld # not comment",
+				"SINGLE": `hello\n`,
+				"URL":    "https://example.com/#anchor",
+			},
+		},
+		{
+			title: "escaped double quote",
+			input: `FOO="say \"hello\"" # comment`,
+			exp: map[string]string{
+				"FOO": `say "hello"`,
+			},
+		},
+		{
+			title: "no equal sign",
+			input: "FOO=foo\nBAR",
+			isErr: true,
+		},
+		{
+			title: "invalid key",
+			input: "1FOO=foo",
+			isErr: true,
+		},
+		{
+			title: "unclosed quote",
+			input: `FOO="foo`,
+			isErr: true,
+		},
+		{
+			title: "characters after the closing quote",
+			input: `FOO="foo"bar`,
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			envs, err := Parse(strings.NewReader(d.input))
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, envs)
+		})
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"os/signal"
+	"syscall"
 
 	"github.com/mattn/go-colorable"
-	"github.com/suzuki-shunsuke/go-timeout/timeout"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
 )
 
 type Executor struct {
@@ -23,6 +27,8 @@ type Result struct {
 	Stdout         string
 	Stderr         string
 	CombinedOutput string
+	// Signal is the signal which github-comment received and forwarded to the command. It's nil if no signal is received
+	Signal os.Signal
 }
 
 type Params struct {
@@ -31,6 +37,18 @@ type Params struct {
 	Stdin io.Reader
 }
 
+// forwardedSignals are signals which are forwarded to the command.
+// CI services send them when jobs are cancelled.
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM} //nolint:gochecknoglobals
+
+// signalExitCodeBase is added to the signal number to get the exit code of the command killed by the signal, like shells
+const signalExitCodeBase = 128
+
+// Run runs the command.
+// The command runs in its own process group on Unix-like systems unless the standard input is a terminal.
+// If github-comment receives SIGINT or SIGTERM while the command runs, the signal is forwarded to the process group of the command
+// and the output captured until the command exits is returned.
+// If the command is killed by the signal, the exit code is 128 + the signal number.
 func (executor *Executor) Run(ctx context.Context, params *Params) (*Result, error) {
 	cmd := exec.Command(params.Cmd, params.Args...) //nolint:gosec
 	cmd.Stdin = params.Stdin
@@ -44,18 +62,75 @@ func (executor *Executor) Run(ctx context.Context, params *Params) (*Result, err
 	cmd.Stderr = io.MultiWriter(executor.Stderr, uncolorizedStderr, uncolorizedCombinedOutput)
 	cmd.Env = executor.Env
 
-	runner := timeout.NewRunner(0)
-	err := runner.Run(ctx, cmd)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, forwardedSignals...)
+	sig, err := runCommand(ctx, cmd, sigCh)
+	signal.Stop(sigCh)
 	ec := cmd.ProcessState.ExitCode()
+	if ws, ok := getWaitStatus(cmd.ProcessState); ok && ws.Signaled() {
+		ec = signalExitCodeBase + int(ws.Signal())
+	}
 	result := &Result{
 		ExitCode:       ec,
 		Cmd:            cmd.String(),
 		Stdout:         stdout.String(),
 		Stderr:         stderr.String(),
 		CombinedOutput: combinedOutput.String(),
+		Signal:         sig,
 	}
 	if err == nil {
 		return result, nil
 	}
 	return result, fmt.Errorf("run a command: %w", err)
 }
+
+// runCommand starts the command and waits for it.
+// If ctx is cancelled, os.Interrupt is sent to the command once.
+// Signals received from sigCh are forwarded to the command, and the first one is returned.
+func runCommand(ctx context.Context, cmd *exec.Cmd, sigCh <-chan os.Signal) (os.Signal, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start a command: %w", err)
+	}
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- cmd.Wait()
+	}()
+	var received os.Signal
+	ctxDone := ctx.Done()
+	for {
+		select {
+		case err := <-waitCh:
+			return received, err
+		case <-ctxDone:
+			// a nil channel is never selected, so the signal is sent only once
+			ctxDone = nil
+			if err := signalCommand(cmd.Process, os.Interrupt); err != nil {
+				logrus.WithError(err).Warn("send a signal to the command")
+			}
+		case sig := <-sigCh:
+			if received == nil {
+				received = sig
+			}
+			logrus.WithField("signal", sig.String()).Info("forward the signal to the command")
+			if err := signalCommand(cmd.Process, sig); err != nil {
+				logrus.WithError(err).Warn("forward the signal to the command")
+			}
+		}
+	}
+}
+
+// isTerminal returns true if r is a terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func getWaitStatus(state *os.ProcessState) (syscall.WaitStatus, bool) {
+	var ws syscall.WaitStatus
+	if state == nil {
+		return ws, false
+	}
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	return ws, ok
+}
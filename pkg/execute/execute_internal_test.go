@@ -0,0 +1,49 @@
+package execute
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_Run(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("signals aren't supported on Windows")
+	}
+	data := []struct {
+		title  string
+		script string
+		exp    int
+	}{
+		{
+			title:  "exit normally",
+			script: "exit 3",
+			exp:    3,
+		},
+		{
+			title:  "killed by SIGTERM",
+			script: "echo foo; kill -TERM $$",
+			exp:    143,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			executor := &Executor{
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			result, err := executor.Run(context.Background(), &Params{
+				Cmd:  "sh",
+				Args: []string{"-c", d.script},
+			})
+			require.NotNil(t, err)
+			require.Equal(t, d.exp, result.ExitCode)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+//go:build unix
+
+package execute
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readyWriter closes ready when the command outputs "ready".
+type readyWriter struct {
+	ready chan struct{}
+	once  sync.Once
+}
+
+func (w *readyWriter) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), "ready") {
+		w.once.Do(func() {
+			close(w.ready)
+		})
+	}
+	return len(p), nil
+}
+
+// TestExecutor_Run_signal sends a signal to the test process itself, so it doesn't run in parallel with other tests.
+func TestExecutor_Run_signal(t *testing.T) { //nolint:paralleltest
+	stdout := &readyWriter{
+		ready: make(chan struct{}),
+	}
+	executor := &Executor{
+		Stdout: stdout,
+		Stderr: io.Discard,
+	}
+	// the command counts received SIGINTs. If the signal is forwarded repeatedly, the count is more than 1
+	script := `n=0
+trap 'n=$((n+1))' INT
+echo ready
+i=0
+while [ "$i" -lt 10 ]; do
+  sleep 0.1
+  i=$((i+1))
+done
+echo "count=$n"
+exit 3`
+	go func() {
+		select {
+		case <-stdout.ready:
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+		case <-time.After(10 * time.Second):
+		}
+	}()
+	result, err := executor.Run(context.Background(), &Params{
+		Cmd:  "sh",
+		Args: []string{"-c", script},
+	})
+	require.NotNil(t, err)
+	require.Equal(t, 3, result.ExitCode)
+	require.Equal(t, syscall.SIGINT, result.Signal)
+	require.Contains(t, result.Stdout, "count=1")
+}
+
+func Test_setProcessGroup(t *testing.T) {
+	t.Parallel()
+	r, w, err := os.Pipe()
+	require.Nil(t, err)
+	defer r.Close()
+	defer w.Close()
+	data := []struct {
+		title string
+		stdin io.Reader
+	}{
+		{
+			title: "no standard input",
+		},
+		{
+			title: "reader",
+			stdin: strings.NewReader("foo"),
+		},
+		{
+			title: "pipe",
+			stdin: r,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			cmd := exec.Command("true")
+			cmd.Stdin = d.stdin
+			setProcessGroup(cmd)
+			require.NotNil(t, cmd.SysProcAttr)
+			require.True(t, cmd.SysProcAttr.Setpgid)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package execute
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalCommand kills the command because sending signals such as os.Interrupt isn't supported on Windows.
+func signalCommand(proc *os.Process, sig os.Signal) error {
+	return proc.Kill() //nolint:wrapcheck
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package execute
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs the command in its own process group unless the standard input is a terminal.
+// Otherwise signals sent to the process group of the command are also sent to github-comment and its parent.
+// A command out of the foreground process group gets SIGTTIN and hangs when it reads the terminal,
+// so interactive commands keep the inherited process group and signals are sent only to the command.
+func setProcessGroup(cmd *exec.Cmd) {
+	if isTerminal(cmd.Stdin) {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalCommand sends the signal to the process group of the command.
+// If the command doesn't lead its own process group, the signal is sent only to the command
+// so that github-comment never receives the signal which it sent.
+func signalCommand(proc *os.Process, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("unsupported signal: %s", sig)
+	}
+	pgid, err := syscall.Getpgid(proc.Pid)
+	if err != nil || pgid != proc.Pid {
+		return proc.Signal(s) //nolint:wrapcheck
+	}
+	return syscall.Kill(-pgid, s) //nolint:wrapcheck
+}
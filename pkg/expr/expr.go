@@ -10,7 +10,7 @@ import (
 type Expr struct{}
 
 func (*Expr) Match(expression string, params interface{}) (bool, error) {
-	prog, err := expr.Compile(expression, expr.Env(params), expr.AsBool())
+	prog, err := expr.Compile(expression, append([]expr.Option{expr.Env(params), expr.AsBool()}, functionOptions()...)...)
 	if err != nil {
 		return false, fmt.Errorf("compile an expression: "+expression+": %w", err)
 	}
@@ -30,7 +30,7 @@ type Program interface {
 
 func (*Expr) Compile(expression string) (Program, error) {
 	prog := Prog{}
-	prg, err := expr.Compile(expression, expr.AsBool())
+	prg, err := expr.Compile(expression, append([]expr.Option{expr.AsBool()}, functionOptions()...)...)
 	if err != nil {
 		return &prog, fmt.Errorf("compile an expression: "+expression+": %w", err)
 	}
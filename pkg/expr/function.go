@@ -0,0 +1,40 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/antonmedv/expr"
+)
+
+var patterns sync.Map //nolint:gochecknoglobals
+
+// CountMatches returns the number of matches of the regular expression pattern in s.
+// Compiled patterns are cached.
+func CountMatches(pattern, s string) (int, error) {
+	var re *regexp.Regexp
+	if a, ok := patterns.Load(pattern); ok {
+		re = a.(*regexp.Regexp) //nolint:forcetypeassert
+	} else {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return 0, fmt.Errorf("compile a regular expression %s: %w", pattern, err)
+		}
+		patterns.Store(pattern, r)
+		re = r
+	}
+	return len(re.FindAllStringIndex(s, -1)), nil
+}
+
+// functionOptions returns options to register functions which are available in expressions.
+// Functions are registered once when the expression is compiled.
+func functionOptions() []expr.Option {
+	return []expr.Option{
+		expr.Function("countMatches", func(params ...interface{}) (interface{}, error) {
+			pattern, _ := params[0].(string)
+			s, _ := params[1].(string)
+			return CountMatches(pattern, s)
+		}, new(func(string, string) int)),
+	}
+}
@@ -0,0 +1,48 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMatches(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		pattern string
+		s       string
+		exp     int
+		isErr   bool
+	}{
+		{
+			title:   "no match",
+			pattern: "ERROR",
+			s:       "hello",
+		},
+		{
+			title:   "matches",
+			pattern: "ERROR|FATAL",
+			s:       "ERROR foo\nINFO bar\nFATAL baz\nERROR",
+			exp:     3,
+		},
+		{
+			title:   "invalid pattern",
+			pattern: "(",
+			isErr:   true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			n, err := CountMatches(d.pattern, d.s)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, n)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultAPIBaseURL is the base URL of GitHub REST API.
+const DefaultAPIBaseURL = "https://api.github.com/"
+
+// AppParam is credentials of a GitHub App installation.
+type AppParam struct {
+	AppID          int64
+	InstallationID int64
+	// PrivateKey is the PEM encoded private key of the GitHub App
+	PrivateKey []byte
+	// BaseURL is the base URL of GitHub REST API. If it's empty, DefaultAPIBaseURL is used
+	BaseURL string
+	// HTTPClient is used to mint installation access tokens. If it's nil, http.DefaultClient is used
+	HTTPClient *http.Client
+}
+
+// appTokenSource mints installation access tokens of a GitHub App.
+type appTokenSource struct {
+	ctx            context.Context //nolint:containedctx
+	httpClient     *http.Client
+	baseURL        string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	now            func() time.Time
+}
+
+// NewAppTokenSource returns a token source which mints installation access tokens of the GitHub App.
+// A token is reused until it expires, and a new token is minted after that, so long running processes keep working.
+func NewAppTokenSource(ctx context.Context, param *AppParam) (oauth2.TokenSource, error) {
+	key, err := parsePrivateKey(param.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := param.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultAPIBaseURL
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	httpClient := param.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{
+		ctx:            ctx,
+		httpClient:     httpClient,
+		baseURL:        baseURL,
+		appID:          param.AppID,
+		installationID: param.InstallationID,
+		key:            key,
+		now:            time.Now,
+	}), nil
+}
+
+// parsePrivateKey parses a PEM encoded RSA private key in PKCS #1 or PKCS #8.
+func parsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("the private key of the GitHub App isn't PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse the private key of the GitHub App: %w", err)
+	}
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key of the GitHub App isn't a RSA private key")
+	}
+	return key, nil
+}
+
+// jwt returns a JSON Web Token to authenticate as the GitHub App.
+// https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (s *appTokenSource) jwt() (string, error) {
+	now := s.now()
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal a JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]int64{
+		// issued 60 seconds in the past to allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		// the max expiration is 10 minutes
+		"exp": now.Add(9 * time.Minute).Unix(), //nolint:gomnd
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal JWT claims: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign a JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Token mints an installation access token.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt()
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%sapp/installations/%d/access_tokens", s.baseURL, s.installationID)
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create a request to mint an installation access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mint an installation access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("mint an installation access token: status code %d: %s", resp.StatusCode, string(b))
+	}
+	body := &struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return nil, fmt.Errorf("decode an installation access token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: body.Token,
+		TokenType:   "Bearer",
+		Expiry:      body.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppTokenSource_Token(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048) //nolint:gomnd
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/2/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		i := strings.LastIndex(jwt, ".")
+		if i == -1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(jwt[i+1:])
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		digest := sha256.Sum256([]byte(jwt[:i]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": "ghs_xxx", "expires_at": "2023-01-01T01:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	pemKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	parsedKey, err := parsePrivateKey(pemKey)
+	require.Nil(t, err)
+	ts := &appTokenSource{
+		ctx:            context.Background(),
+		httpClient:     server.Client(),
+		baseURL:        server.URL + "/",
+		appID:          1,
+		installationID: 2,
+		key:            parsedKey,
+		now: func() time.Time {
+			return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	token, err := ts.Token()
+	require.Nil(t, err)
+	require.Equal(t, "ghs_xxx", token.AccessToken)
+	require.Equal(t, time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC), token.Expiry)
+}
+
+func Test_parsePrivateKey(t *testing.T) {
+	t.Parallel()
+	_, err := parsePrivateKey([]byte("foo"))
+	require.NotNil(t, err)
+}
+
+func Test_newTokenSource(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048) //nolint:gomnd
+	if err != nil {
+		t.Fatal(err)
+	}
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/app/installations/2/access_tokens" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requested = true
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": "ghs_xxx", "expires_at": "2023-01-01T01:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	ts, err := newTokenSource(context.Background(), &ParamNew{
+		Token:      "xxx",
+		GHEBaseURL: server.URL,
+		App: &AppParam{
+			AppID:          1,
+			InstallationID: 2,
+			PrivateKey: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(key),
+			}),
+		},
+	}, server.Client())
+	require.Nil(t, err)
+	require.False(t, requested, "the installation access token must be minted lazily")
+	token, err := ts.Token()
+	require.Nil(t, err)
+	require.True(t, requested)
+	require.Equal(t, "ghs_xxx", token.AccessToken)
+
+	ts, err = newTokenSource(context.Background(), &ParamNew{Token: "xxx"}, server.Client())
+	require.Nil(t, err)
+	token, err = ts.Token()
+	require.Nil(t, err)
+	require.Equal(t, "xxx", token.AccessToken)
+}
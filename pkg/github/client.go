@@ -3,6 +3,9 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/shurcooL/githubv4"
@@ -10,32 +13,70 @@ import (
 )
 
 type Client struct {
-	issue IssuesService
-	pr    PullRequestsService
-	repo  RepositoriesService
-	user  UsersService
-	ghV4  V4Client
+	issue    IssuesService
+	pr       PullRequestsService
+	repo     RepositoriesService
+	user     UsersService
+	team     TeamsService
+	reaction ReactionsService
+	ghV4     V4Client
+	// graphQLCost is the total cost of GraphQL queries
+	graphQLCost int
+	// listCommentsConcurrency is the number of pages of comments which are fetched concurrently
+	listCommentsConcurrency int
+	mutex                   sync.Mutex
+	// login is the cached login of the authenticated user
+	login      string
+	loginMutex sync.Mutex
 }
 
 type ParamNew struct {
 	Token              string
 	GHEBaseURL         string
 	GHEGraphQLEndpoint string
+	// RetryMaxAttempts is the max number of attempts of an API call. If it's less than 2, API calls aren't retried
+	RetryMaxAttempts     int
+	RetryInitialInterval time.Duration
+	// App takes precedence over Token. It's used to authenticate as a GitHub App installation
+	App *AppParam
+	// GHEUploadURL is the upload URL of GitHub Enterprise Server. If it's empty, it's derived from GHEBaseURL
+	GHEUploadURL string
+	// GHEAPIPath is the path of REST API such as "api/v3". If it's empty, "api/v3" is used
+	GHEAPIPath string
+	// ListCommentsConcurrency is the number of pages of comments which are fetched concurrently.
+	// If it's zero, DefaultListCommentsConcurrency is used, so pages are fetched sequentially only by GraphQL API.
+	// It's capped by MaxListCommentsConcurrency
+	ListCommentsConcurrency int
 }
 
 func New(ctx context.Context, param *ParamNew) (*Client, error) {
-	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: param.Token},
-	))
-	client := &Client{}
+	transport := http.DefaultTransport
+	if param.RetryMaxAttempts > 1 {
+		transport = newRetryTransport(transport, param.RetryMaxAttempts, param.RetryInitialInterval)
+	}
+	ts, err := newTokenSource(ctx, param, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: ts,
+			Base:   transport,
+		},
+	}
+	client := &Client{
+		listCommentsConcurrency: getListCommentsConcurrency(param.ListCommentsConcurrency),
+	}
 	if param.GHEBaseURL == "" {
 		gh := github.NewClient(httpClient)
 		client.issue = gh.Issues
 		client.repo = gh.Repositories
 		client.user = gh.Users
 		client.pr = gh.PullRequests
+		client.team = gh.Teams
+		client.reaction = gh.Reactions
 	} else {
-		gh, err := github.NewEnterpriseClient(param.GHEBaseURL, param.GHEBaseURL, httpClient)
+		gh, err := newEnterpriseClient(param, httpClient)
 		if err != nil {
 			return nil, fmt.Errorf("initialize GitHub Enterprise API Client: %w", err)
 		}
@@ -43,6 +84,8 @@ func New(ctx context.Context, param *ParamNew) (*Client, error) {
 		client.repo = gh.Repositories
 		client.user = gh.Users
 		client.pr = gh.PullRequests
+		client.team = gh.Teams
+		client.reaction = gh.Reactions
 	}
 	if param.GHEGraphQLEndpoint == "" {
 		client.ghV4 = githubv4.NewClient(httpClient)
@@ -53,6 +96,20 @@ func New(ctx context.Context, param *ParamNew) (*Client, error) {
 	return client, nil
 }
 
+// newTokenSource returns a token source of the GitHub App installation if App is set, otherwise a token source of Token.
+// Installation access tokens are minted by httpClient, so retries and the base URL of GitHub Enterprise Server are applied.
+func newTokenSource(ctx context.Context, param *ParamNew, httpClient *http.Client) (oauth2.TokenSource, error) {
+	if param.App == nil {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: param.Token}), nil
+	}
+	app := *param.App
+	app.HTTPClient = httpClient
+	if app.BaseURL == "" && param.GHEBaseURL != "" {
+		app.BaseURL, _ = EnterpriseURLs(param.GHEBaseURL, param.GHEUploadURL, param.GHEAPIPath)
+	}
+	return NewAppTokenSource(ctx, &app)
+}
+
 type V4Client interface {
 	Mutate(ctx context.Context, m interface{}, input githubv4.Input, variables map[string]interface{}) error
 	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
@@ -61,17 +118,31 @@ type V4Client interface {
 type IssuesService interface {
 	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
 	EditComment(ctx context.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	DeleteComment(ctx context.Context, owner string, repo string, commentID int64) (*github.Response, error)
+	ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
 }
 
 type RepositoriesService interface {
 	CreateComment(ctx context.Context, owner, repo, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error)
 	UpdateComment(ctx context.Context, owner, repo string, id int64, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error)
+	CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
+}
+
+type ReactionsService interface {
+	CreateIssueCommentReaction(ctx context.Context, owner, repo string, id int64, content string) (*github.Reaction, *github.Response, error)
 }
 
 type UsersService interface {
 	Get(ctx context.Context, user string) (*github.User, *github.Response, error)
 }
 
+type TeamsService interface {
+	GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, *github.Response, error)
+}
+
 type PullRequestsService interface {
 	ListPullRequestsWithCommit(ctx context.Context, owner, repo, sha string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	Get(ctx context.Context, owner, repo string, number int) (*github.PullRequest, *github.Response, error)
+	CreateReview(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	ListReviewComments(ctx context.Context, owner, repo string, number int, reviewID int64, opts *github.ListOptions) ([]*github.PullRequestComment, *github.Response, error)
 }
@@ -8,16 +8,45 @@ import (
 )
 
 type Comment struct {
-	PRNumber       int
-	CommentID      int64
-	Org            string
-	Repo           string
-	Body           string
-	BodyForTooLong string
-	SHA1           string
-	HideOldComment string
-	TemplateKey    string
-	Vars           map[string]interface{}
+	PRNumber        int
+	CommentID       int64
+	Org             string
+	Repo            string
+	Body            string
+	BodyForTooLong  string
+	SHA1            string
+	HideOldComment  string
+	TemplateKey     string
+	Vars            map[string]interface{}
+	Footers         []*Footer
+	Metadata        string
+	TooLongStrategy string
+	// Debug is a collapsed section for troubleshooting which is appended to the comment
+	Debug string
+	// ReplacedCommentID is the id of the comment which is deleted after the comment is created
+	ReplacedCommentID int64
+	// MinimizeOnCreate minimizes the comment immediately after the comment is posted
+	MinimizeOnCreate bool
+	// NodeID is the GraphQL node id of the posted comment. It's set after the comment is posted
+	NodeID string
+	// URL is the HTML URL of the posted comment. It's set after the comment is posted
+	URL string
+	// Path and Line are the file path and the line number of the pull request diff.
+	// If Path is set, the comment is posted as a review comment anchored to the line instead of an issue comment
+	Path string
+	Line int
+	// FitBody renders the body again so that the length of the body is less than or equal to the given length.
+	// It's nil if the body can't be re-rendered
+	FitBody func(limit int) (string, error)
+	// MentionBody renders the body whose mentions are validated, such as the body without the command output.
+	// It's nil if the whole body is validated
+	MentionBody func() (string, error)
+}
+
+// Footer is a rendered footer which is appended to the comment if When is matched.
+type Footer struct {
+	When string
+	Body string
 }
 
 // `graphql:"IssueComment(isMinimized: false, viewerCanMinimize: true)"`
@@ -29,6 +58,9 @@ type IssueComment struct {
 		Login string
 	}
 	CreatedAt string
+	UpdatedAt string
+	// AuthorAssociation is the author's association with the repository such as MEMBER and OWNER
+	AuthorAssociation string
 	// TODO remove
 	IsMinimized       bool
 	ViewerCanMinimize bool
@@ -36,35 +68,47 @@ type IssueComment struct {
 
 func (client *Client) sendIssueComment(ctx context.Context, cmt *Comment, body string) error {
 	if cmt.CommentID != 0 {
-		if _, _, err := client.issue.EditComment(ctx, cmt.Org, cmt.Repo, cmt.CommentID, &github.IssueComment{
+		comment, _, err := client.issue.EditComment(ctx, cmt.Org, cmt.Repo, cmt.CommentID, &github.IssueComment{
 			Body: github.String(body),
-		}); err != nil {
+		})
+		if err != nil {
 			return fmt.Errorf("edit a issue or pull request comment by GitHub API: %w", err)
 		}
+		cmt.NodeID = comment.GetNodeID()
+		cmt.URL = comment.GetHTMLURL()
 		return nil
 	}
-	if _, _, err := client.issue.CreateComment(ctx, cmt.Org, cmt.Repo, cmt.PRNumber, &github.IssueComment{
+	comment, _, err := client.issue.CreateComment(ctx, cmt.Org, cmt.Repo, cmt.PRNumber, &github.IssueComment{
 		Body: github.String(body),
-	}); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("create a comment to issue or pull request by GitHub API: %w", err)
 	}
+	cmt.NodeID = comment.GetNodeID()
+	cmt.URL = comment.GetHTMLURL()
 	return nil
 }
 
 func (client *Client) sendCommitComment(ctx context.Context, cmt *Comment, body string) error {
 	if cmt.CommentID != 0 {
-		if _, _, err := client.repo.UpdateComment(ctx, cmt.Org, cmt.Repo, cmt.CommentID, &github.RepositoryComment{
+		comment, _, err := client.repo.UpdateComment(ctx, cmt.Org, cmt.Repo, cmt.CommentID, &github.RepositoryComment{
 			Body: github.String(body),
-		}); err != nil {
+		})
+		if err != nil {
 			return fmt.Errorf("update a commit comment by GitHub API: %w", err)
 		}
+		cmt.NodeID = comment.GetNodeID()
+		cmt.URL = comment.GetHTMLURL()
 		return nil
 	}
-	if _, _, err := client.repo.CreateComment(ctx, cmt.Org, cmt.Repo, cmt.SHA1, &github.RepositoryComment{
+	comment, _, err := client.repo.CreateComment(ctx, cmt.Org, cmt.Repo, cmt.SHA1, &github.RepositoryComment{
 		Body: github.String(body),
-	}); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("create a commit comment by GitHub API: %w", err)
 	}
+	cmt.NodeID = comment.GetNodeID()
+	cmt.URL = comment.GetHTMLURL()
 	return nil
 }
 
@@ -79,6 +123,10 @@ func (client *Client) createComment(ctx context.Context, cmt *Comment, tooLong b
 	return client.sendCommitComment(ctx, cmt, body)
 }
 
+// MaxCommentLength is the max length of a comment body.
+// The length is counted by CommentLength.
+const MaxCommentLength = 65536
+
 func (client *Client) CreateComment(ctx context.Context, cmt *Comment) error {
-	return client.createComment(ctx, cmt, len(cmt.Body) > 65536) //nolint:gomnd
+	return client.createComment(ctx, cmt, CommentLength(cmt.Body) > MaxCommentLength)
 }
@@ -0,0 +1,13 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+func (client *Client) DeleteComment(ctx context.Context, org, repo string, commentID int64) error {
+	if _, err := client.issue.DeleteComment(ctx, org, repo, commentID); err != nil {
+		return fmt.Errorf("delete an issue or pull request comment by GitHub API: %w", err)
+	}
+	return nil
+}
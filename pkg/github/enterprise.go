@@ -0,0 +1,60 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+)
+
+// DefaultGHEAPIPath is the path of GitHub Enterprise Server REST API.
+const DefaultGHEAPIPath = "api/v3"
+
+// EnterpriseURLs returns the REST API base URL and the upload URL of GitHub Enterprise Server.
+// apiPath replaces the standard path /api/v3 for instances which serve the API on a non-standard path.
+// If apiPath is "/", the API is served on baseURL itself.
+// If uploadURL is empty, it's derived from the host root as go-github does, which is baseURL without the standard path /api/v3.
+func EnterpriseURLs(baseURL, uploadURL, apiPath string) (string, string) {
+	root := withTrailingSlash(baseURL)
+	base := root
+	switch {
+	case apiPath == "":
+		if strings.HasSuffix(root, "/"+DefaultGHEAPIPath+"/") {
+			root = strings.TrimSuffix(root, DefaultGHEAPIPath+"/")
+		} else {
+			base += DefaultGHEAPIPath + "/"
+		}
+	case strings.Trim(apiPath, "/") != "":
+		base += strings.Trim(apiPath, "/") + "/"
+	}
+	if uploadURL == "" {
+		uploadURL = root + "api/uploads/"
+	}
+	return base, withTrailingSlash(uploadURL)
+}
+
+func withTrailingSlash(s string) string {
+	if strings.HasSuffix(s, "/") {
+		return s
+	}
+	return s + "/"
+}
+
+// newEnterpriseClient returns a client of GitHub Enterprise Server.
+func newEnterpriseClient(param *ParamNew, httpClient *http.Client) (*github.Client, error) {
+	baseURL, uploadURL := EnterpriseURLs(param.GHEBaseURL, param.GHEUploadURL, param.GHEAPIPath)
+	gh := github.NewClient(httpClient)
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse the base URL of GitHub Enterprise Server: %w", err)
+	}
+	gh.BaseURL = u
+	u, err = url.Parse(uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse the upload URL of GitHub Enterprise Server: %w", err)
+	}
+	gh.UploadURL = u
+	return gh, nil
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterpriseURLs(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		baseURL   string
+		uploadURL string
+		apiPath   string
+		expBase   string
+		expUpload string
+	}{
+		{
+			title:     "default",
+			baseURL:   "https://ghe.example.com",
+			expBase:   "https://ghe.example.com/api/v3/",
+			expUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			title:     "base url includes the api path",
+			baseURL:   "https://ghe.example.com/api/v3/",
+			expBase:   "https://ghe.example.com/api/v3/",
+			expUpload: "https://ghe.example.com/api/uploads/",
+		},
+		{
+			title:     "base url includes the api path under a sub path",
+			baseURL:   "https://proxy.example.com/github/api/v3",
+			expBase:   "https://proxy.example.com/github/api/v3/",
+			expUpload: "https://proxy.example.com/github/api/uploads/",
+		},
+		{
+			title:     "custom api path and upload url",
+			baseURL:   "https://proxy.example.com/github/",
+			uploadURL: "https://uploads.example.com",
+			apiPath:   "/rest/",
+			expBase:   "https://proxy.example.com/github/rest/",
+			expUpload: "https://uploads.example.com/",
+		},
+		{
+			title:     "api on the root",
+			baseURL:   "https://proxy.example.com/github",
+			apiPath:   "/",
+			expBase:   "https://proxy.example.com/github/",
+			expUpload: "https://proxy.example.com/github/api/uploads/",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			base, upload := EnterpriseURLs(d.baseURL, d.uploadURL, d.apiPath)
+			require.Equal(t, d.expBase, base)
+			require.Equal(t, d.expUpload, upload)
+		})
+	}
+}
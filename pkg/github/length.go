@@ -0,0 +1,50 @@
+package github
+
+import "unicode/utf8"
+
+// CommentLength returns the length of a comment body as GitHub counts it.
+// GitHub limits the length of a comment body by UTF-16 code units,
+// so a character outside the Basic Multilingual Plane such as an emoji is counted as two.
+func CommentLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeLength(r)
+	}
+	return n
+}
+
+// PrefixIndex returns the largest byte index i such that CommentLength(s[:i]) <= limit.
+// i is always the start of a rune.
+func PrefixIndex(s string, limit int) int {
+	n := 0
+	for i, r := range s {
+		n += runeLength(r)
+		if n > limit {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// SuffixIndex returns the smallest byte index i such that CommentLength(s[i:]) <= limit.
+// i is always the start of a rune.
+func SuffixIndex(s string, limit int) int {
+	n := 0
+	i := len(s)
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		n += runeLength(r)
+		if n > limit {
+			return i
+		}
+		i -= size
+	}
+	return 0
+}
+
+func runeLength(r rune) int {
+	if r >= 0x10000 { //nolint:gomnd
+		return 2 //nolint:gomnd
+	}
+	return 1
+}
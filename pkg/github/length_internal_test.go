@@ -0,0 +1,122 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentLength(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		s     string
+		exp   int
+	}{
+		{
+			title: "ascii",
+			s:     "hello",
+			exp:   5,
+		},
+		{
+			title: "multibyte characters are counted as one",
+			s:     "こんにちは",
+			exp:   5,
+		},
+		{
+			title: "emoji is counted as two",
+			s:     "a😀",
+			exp:   3,
+		},
+		{
+			title: "exactly the max length",
+			s:     strings.Repeat("😀", MaxCommentLength/2),
+			exp:   MaxCommentLength,
+		},
+		{
+			title: "over the max length though the number of characters is less than the max",
+			s:     strings.Repeat("😀", MaxCommentLength/2) + "a",
+			exp:   MaxCommentLength + 1,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, CommentLength(d.s))
+		})
+	}
+}
+
+func TestPrefixIndex(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		s     string
+		limit int
+		exp   int
+	}{
+		{
+			title: "not exceeded",
+			s:     "hello",
+			limit: 10,
+			exp:   5,
+		},
+		{
+			title: "an emoji isn't split",
+			s:     "a😀b",
+			limit: 2,
+			exp:   1,
+		},
+		{
+			title: "an emoji fits",
+			s:     "a😀b",
+			limit: 3,
+			exp:   5,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, PrefixIndex(d.s, d.limit))
+		})
+	}
+}
+
+func TestSuffixIndex(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		s     string
+		limit int
+		exp   int
+	}{
+		{
+			title: "not exceeded",
+			s:     "hello",
+			limit: 10,
+			exp:   0,
+		},
+		{
+			title: "an emoji isn't split",
+			s:     "a😀b",
+			limit: 2,
+			exp:   5,
+		},
+		{
+			title: "an emoji fits",
+			s:     "a😀b",
+			limit: 3,
+			exp:   1,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, SuffixIndex(d.s, d.limit))
+		})
+	}
+}
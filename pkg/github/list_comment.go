@@ -19,14 +19,16 @@ func (client *Client) listIssueComment(ctx context.Context, pr *PullRequest) ([]
 		Repository struct {
 			Issue struct {
 				Comments struct {
-					Nodes    []*IssueComment
-					PageInfo struct {
+					Nodes      []*IssueComment
+					TotalCount int
+					PageInfo   struct {
 						EndCursor   githubv4.String
 						HasNextPage bool
 					}
 				} `graphql:"comments(first: 100, after: $commentsCursor)"` // 100 per page.
 			} `graphql:"issue(number: $issueNumber)"`
 		} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+		RateLimit rateLimit
 	}
 	variables := map[string]interface{}{
 		"repositoryOwner": githubv4.String(pr.Org),
@@ -40,10 +42,14 @@ func (client *Client) listIssueComment(ctx context.Context, pr *PullRequest) ([]
 		if err := client.ghV4.Query(ctx, &q, variables); err != nil {
 			return nil, fmt.Errorf("list issue comments by GitHub API: %w", err)
 		}
+		client.recordRateLimit("list issue comments", q.RateLimit)
 		allComments = append(allComments, q.Repository.Issue.Comments.Nodes...)
 		if !q.Repository.Issue.Comments.PageInfo.HasNextPage {
 			break
 		}
+		if client.listCommentsConcurrency > 1 && len(allComments) == commentsPerPage {
+			return client.listRemainingComments(ctx, pr, allComments, q.Repository.Issue.Comments.TotalCount)
+		}
 		variables["commentsCursor"] = githubv4.NewString(q.Repository.Issue.Comments.PageInfo.EndCursor)
 	}
 	return allComments, nil
@@ -55,14 +61,16 @@ func (client *Client) listPRComment(ctx context.Context, pr *PullRequest) ([]*Is
 		Repository struct {
 			PullRequest struct {
 				Comments struct {
-					Nodes    []*IssueComment
-					PageInfo struct {
+					Nodes      []*IssueComment
+					TotalCount int
+					PageInfo   struct {
 						EndCursor   githubv4.String
 						HasNextPage bool
 					}
 				} `graphql:"comments(first: 100, after: $commentsCursor)"` // 100 per page.
 			} `graphql:"pullRequest(number: $issueNumber)"`
 		} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+		RateLimit rateLimit
 	}
 	variables := map[string]interface{}{
 		"repositoryOwner": githubv4.String(pr.Org),
@@ -76,10 +84,14 @@ func (client *Client) listPRComment(ctx context.Context, pr *PullRequest) ([]*Is
 		if err := client.ghV4.Query(ctx, &q, variables); err != nil {
 			return nil, fmt.Errorf("list issue comments by GitHub API: %w", err)
 		}
+		client.recordRateLimit("list pull request comments", q.RateLimit)
 		allComments = append(allComments, q.Repository.PullRequest.Comments.Nodes...)
 		if !q.Repository.PullRequest.Comments.PageInfo.HasNextPage {
 			break
 		}
+		if client.listCommentsConcurrency > 1 && len(allComments) == commentsPerPage {
+			return client.listRemainingComments(ctx, pr, allComments, q.Repository.PullRequest.Comments.TotalCount)
+		}
 		variables["commentsCursor"] = githubv4.NewString(q.Repository.PullRequest.Comments.PageInfo.EndCursor)
 	}
 	return allComments, nil
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultListCommentsConcurrency is the default number of pages of comments which are fetched concurrently.
+	// Pages are fetched sequentially by default because fetching them concurrently consumes more API calls
+	DefaultListCommentsConcurrency = 1
+	// MaxListCommentsConcurrency caps the concurrency to avoid secondary rate limits of GitHub API
+	MaxListCommentsConcurrency = 10
+	commentsPerPage            = 100
+)
+
+// getListCommentsConcurrency returns the concurrency of listing comments.
+// If n isn't positive, the default value is returned.
+func getListCommentsConcurrency(n int) int {
+	if n <= 0 {
+		return DefaultListCommentsConcurrency
+	}
+	if n > MaxListCommentsConcurrency {
+		return MaxListCommentsConcurrency
+	}
+	return n
+}
+
+// listRemainingComments lists comments after the first page concurrently.
+// GraphQL API paginates comments by cursors, so pages can't be fetched concurrently.
+// Instead, node ids of comments are listed by REST API which paginates by page numbers,
+// then the comments are fetched by the node ids with GraphQL API.
+// The order of comments is preserved.
+// Comments created while listing can shift pages, so duplicated comments are removed.
+func (client *Client) listRemainingComments(ctx context.Context, pr *PullRequest, firstPage []*IssueComment, totalCount int) ([]*IssueComment, error) {
+	numPages := (totalCount + commentsPerPage - 1) / commentsPerPage
+	if numPages < 2 { //nolint:gomnd
+		return firstPage, nil
+	}
+	pages := make([][]*IssueComment, numPages)
+	pages[0] = firstPage
+
+	concurrency := client.listCommentsConcurrency
+	if concurrency > numPages-1 {
+		concurrency = numPages - 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pageCh := make(chan int)
+	// each worker sends an error at most once
+	errCh := make(chan error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pageCh {
+				cmts, err := client.listCommentsPage(ctx, pr, page)
+				if err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+				pages[page-1] = cmts
+			}
+		}()
+	}
+sendPages:
+	for page := 2; page <= numPages; page++ {
+		select {
+		case pageCh <- page:
+		case <-ctx.Done():
+			break sendPages
+		}
+	}
+	close(pageCh)
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+
+	allComments := make([]*IssueComment, 0, totalCount)
+	ids := make(map[string]struct{}, totalCount)
+	for _, page := range pages {
+		for _, cmt := range page {
+			if _, ok := ids[cmt.ID]; ok {
+				continue
+			}
+			ids[cmt.ID] = struct{}{}
+			allComments = append(allComments, cmt)
+		}
+	}
+	return allComments, nil
+}
+
+// listCommentsPage lists comments of the page.
+func (client *Client) listCommentsPage(ctx context.Context, pr *PullRequest, page int) ([]*IssueComment, error) {
+	comments, _, err := client.issue.ListComments(ctx, pr.Org, pr.Repo, pr.PRNumber, &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			Page:    page,
+			PerPage: commentsPerPage,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list issue comments by GitHub REST API: %w", err)
+	}
+	if len(comments) == 0 {
+		return nil, nil
+	}
+	ids := make([]githubv4.ID, len(comments))
+	for i, comment := range comments {
+		ids[i] = githubv4.ID(comment.GetNodeID())
+	}
+	var q struct {
+		Nodes []*struct {
+			IssueComment IssueComment `graphql:"... on IssueComment"`
+		} `graphql:"nodes(ids: $ids)"`
+		RateLimit rateLimit
+	}
+	if err := client.ghV4.Query(ctx, &q, map[string]interface{}{
+		"ids": ids,
+	}); err != nil {
+		// comments deleted after they are listed by REST API can't be resolved.
+		// The response has null nodes as well as errors, so the null nodes are dropped
+		if !isNodeNotFound(err) || len(q.Nodes) != len(ids) {
+			return nil, fmt.Errorf("get issue comments by GitHub API: %w", err)
+		}
+		logrus.WithError(err).Debug("comments were deleted while listing comments")
+	}
+	client.recordRateLimit("get issue comments", q.RateLimit)
+	ret := make([]*IssueComment, 0, len(q.Nodes))
+	for _, node := range q.Nodes {
+		if node == nil {
+			continue
+		}
+		ret = append(ret, &node.IssueComment)
+	}
+	return ret, nil
+}
+
+// isNodeNotFound returns true if the error of GitHub GraphQL API is that a node isn't found.
+func isNodeNotFound(err error) bool {
+	return strings.Contains(err.Error(), "Could not resolve to a node")
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssues returns comments whose node ids are "1", "2", ..., "total".
+type fakeIssues struct {
+	IssuesService
+	total int
+}
+
+func (f *fakeIssues) ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	start := (opts.Page-1)*opts.PerPage + 1
+	var comments []*github.IssueComment
+	for i := start; i < start+opts.PerPage && i <= f.total; i++ {
+		comments = append(comments, &github.IssueComment{
+			NodeID: github.String(strconv.Itoa(i)),
+		})
+	}
+	return comments, nil, nil
+}
+
+// fakeV4 returns comments of the given node ids.
+// Nodes of deleted ids are null and an error is returned with the nodes like GitHub API.
+type fakeV4 struct {
+	V4Client
+	deleted map[githubv4.ID]struct{}
+}
+
+func (f *fakeV4) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	ids, ok := variables["ids"].([]githubv4.ID)
+	if !ok {
+		return fmt.Errorf("ids aren't given: %+v", variables)
+	}
+	nodes := make([]map[string]interface{}, len(ids))
+	var queryErr error
+	for i, id := range ids {
+		if _, ok := f.deleted[id]; ok {
+			queryErr = fmt.Errorf("Could not resolve to a node with the global id of '%s'", id) //nolint:stylecheck
+			continue
+		}
+		nodes[i] = map[string]interface{}{
+			"IssueComment": map[string]interface{}{
+				"ID": id,
+			},
+		}
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"Nodes": nodes,
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	if err := json.Unmarshal(b, q); err != nil {
+		return err //nolint:wrapcheck
+	}
+	return queryErr
+}
+
+func TestClient_listRemainingComments(t *testing.T) {
+	t.Parallel()
+	const total = 1050
+	client := &Client{
+		issue:                   &fakeIssues{total: total},
+		ghV4:                    &fakeV4{},
+		listCommentsConcurrency: 3,
+	}
+	firstPage := make([]*IssueComment, commentsPerPage)
+	for i := range firstPage {
+		firstPage[i] = &IssueComment{ID: strconv.Itoa(i + 1)}
+	}
+	comments, err := client.listRemainingComments(context.Background(), &PullRequest{
+		Org:      "suzuki-shunsuke",
+		Repo:     "github-comment",
+		PRNumber: 1,
+	}, firstPage, total)
+	require.Nil(t, err)
+	require.Equal(t, total, len(comments))
+	for i, comment := range comments {
+		require.Equal(t, strconv.Itoa(i+1), comment.ID)
+	}
+}
+
+func TestClient_listRemainingComments_deleted(t *testing.T) {
+	t.Parallel()
+	const total = 250
+	client := &Client{
+		issue: &fakeIssues{total: total},
+		ghV4: &fakeV4{
+			deleted: map[githubv4.ID]struct{}{
+				"150": {},
+			},
+		},
+		listCommentsConcurrency: 2,
+	}
+	firstPage := make([]*IssueComment, commentsPerPage)
+	for i := range firstPage {
+		firstPage[i] = &IssueComment{ID: strconv.Itoa(i + 1)}
+	}
+	comments, err := client.listRemainingComments(context.Background(), &PullRequest{
+		Org:      "suzuki-shunsuke",
+		Repo:     "github-comment",
+		PRNumber: 1,
+	}, firstPage, total)
+	require.Nil(t, err)
+	require.Equal(t, total-1, len(comments))
+	for _, comment := range comments {
+		require.NotEqual(t, "150", comment.ID)
+	}
+}
@@ -2,8 +2,10 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
 )
 
@@ -12,9 +14,85 @@ type Mock struct {
 	Silent   bool
 	Login    string
 	PRNumber int
+	// Output is a file path where the comment body is written.
+	// This is useful for snapshot testing of comments
+	Output string
+	// OutputJSON writes the comment body and where the comment is posted to Output as JSON
+	OutputJSON bool
+}
+
+// mockOutput is written to Mock.Output as JSON.
+// Don't change the existing fields so that the output is stable.
+type mockOutput struct {
+	Org      string `json:"org"`
+	Repo     string `json:"repo"`
+	PRNumber int    `json:"pr_number"`
+	SHA1     string `json:"sha1"`
+	// Action is "create" or "update"
+	Action            string `json:"action"`
+	CommentID         int64  `json:"comment_id,omitempty"`
+	ReplacedCommentID int64  `json:"replaced_comment_id,omitempty"`
+	Body              string `json:"body"`
+	// Path and Line are set if the comment is a review comment
+	Path string `json:"path,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// output returns the content written to Output.
+func (mock *Mock) output(cmt *Comment) ([]byte, error) {
+	if !mock.OutputJSON {
+		return []byte(cmt.Body), nil
+	}
+	action := "create"
+	if cmt.CommentID != 0 {
+		action = "update"
+	}
+	b, err := json.MarshalIndent(&mockOutput{
+		Org:               cmt.Org,
+		Repo:              cmt.Repo,
+		PRNumber:          cmt.PRNumber,
+		SHA1:              cmt.SHA1,
+		Action:            action,
+		CommentID:         cmt.CommentID,
+		ReplacedCommentID: cmt.ReplacedCommentID,
+		Body:              cmt.Body,
+		Path:              cmt.Path,
+		Line:              cmt.Line,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal the comment as JSON: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// dryRunURL returns a placeholder URL of the comment because the comment isn't posted actually.
+func dryRunURL(cmt *Comment) string {
+	if cmt.PRNumber != 0 {
+		return fmt.Sprintf("https://github.com/%s/%s/pull/%d#issuecomment-dry-run", cmt.Org, cmt.Repo, cmt.PRNumber)
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/commit/%s#commitcomment-dry-run", cmt.Org, cmt.Repo, cmt.SHA1)
+}
+
+// writeOutput writes the comment to Output if Output is set.
+func (mock *Mock) writeOutput(cmt *Comment) error {
+	if mock.Output == "" {
+		return nil
+	}
+	b, err := mock.output(cmt)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(mock.Output, b, 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write the comment body to a file %s: %w", mock.Output, err)
+	}
+	return nil
 }
 
 func (mock *Mock) CreateComment(ctx context.Context, cmt *Comment) error {
+	cmt.URL = dryRunURL(cmt)
+	if err := mock.writeOutput(cmt); err != nil {
+		return err
+	}
 	if mock.Silent {
 		return nil
 	}
@@ -23,10 +101,44 @@ func (mock *Mock) CreateComment(ctx context.Context, cmt *Comment) error {
 		msg += " issue:" + strconv.Itoa(cmt.PRNumber)
 	}
 	fmt.Fprintln(mock.Stderr, msg+"\n[github-comment][DRYRUN] "+cmt.Body)
+	if cmt.MinimizeOnCreate {
+		fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Minimize the comment")
+	}
 	return nil
 }
 
-func (mock *Mock) HideComment(ctx context.Context, nodeID string) error {
+func (mock *Mock) PostReviewComment(ctx context.Context, cmt *Comment) error {
+	cmt.URL = dryRunURL(cmt)
+	if err := mock.writeOutput(cmt); err != nil {
+		return err
+	}
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Review comment to "+cmt.Org+"/"+cmt.Repo+" pr:"+strconv.Itoa(cmt.PRNumber)+" "+cmt.Path+":"+strconv.Itoa(cmt.Line)+"\n[github-comment][DRYRUN] "+cmt.Body)
+	if cmt.MinimizeOnCreate {
+		fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Minimize the comment")
+	}
+	return nil
+}
+
+func (mock *Mock) AddReaction(ctx context.Context, org, repo string, commentID int64, content string) error {
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintf(mock.Stderr, "[github-comment][DRYRUN] Add a reaction %s to the comment %d\n", content, commentID)
+	return nil
+}
+
+func (mock *Mock) HideComment(ctx context.Context, nodeID string) (bool, error) {
+	return true, nil
+}
+
+func (mock *Mock) DeleteComment(ctx context.Context, org, repo string, commentID int64) error {
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Delete a comment "+org+"/"+repo+" comment_id:"+strconv.FormatInt(commentID, 10))
 	return nil
 }
 
@@ -34,6 +146,18 @@ func (mock *Mock) ListComments(ctx context.Context, pr *PullRequest) ([]*IssueCo
 	return nil, nil
 }
 
+func (mock *Mock) ListReviewThreads(ctx context.Context, pr *PullRequest) ([]*ReviewThread, error) {
+	return nil, nil
+}
+
+func (mock *Mock) ResolveReviewThread(ctx context.Context, threadID string) error {
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Resolve a review thread "+threadID)
+	return nil
+}
+
 func (mock *Mock) GetAuthenticatedUser(ctx context.Context) (string, error) {
 	return mock.Login, nil
 }
@@ -41,3 +165,34 @@ func (mock *Mock) GetAuthenticatedUser(ctx context.Context) (string, error) {
 func (mock *Mock) PRNumberWithSHA(ctx context.Context, owner, repo, sha string) (int, error) {
 	return mock.PRNumber, nil
 }
+
+func (mock *Mock) PRInfo(ctx context.Context, owner, repo string, number int) (*PRInfo, error) {
+	return &PRInfo{}, nil
+}
+
+func (mock *Mock) CreateSuggestionReview(ctx context.Context, org, repo string, prNumber int, body string, suggestions []*Suggestion) error {
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Create a review to "+org+"/"+repo+" pr:"+strconv.Itoa(prNumber)+"\n[github-comment][DRYRUN] "+body)
+	for _, s := range suggestions {
+		fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] "+s.File+":"+strconv.Itoa(s.Line)+"\n"+s.SuggestionBody())
+	}
+	return nil
+}
+
+func (mock *Mock) TeamExists(ctx context.Context, org, team string) (bool, error) {
+	return true, nil
+}
+
+func (mock *Mock) UserExists(ctx context.Context, login string) (bool, error) {
+	return true, nil
+}
+
+func (mock *Mock) CreateStatus(ctx context.Context, org, repo, sha, state, statusContext, description, targetURL string) error {
+	if mock.Silent {
+		return nil
+	}
+	fmt.Fprintln(mock.Stderr, "[github-comment][DRYRUN] Create a commit status to "+org+"/"+repo+" sha1:"+sha+" context:"+statusContext+" state:"+state)
+	return nil
+}
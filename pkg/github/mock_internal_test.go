@@ -0,0 +1,96 @@
+package github
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMock_output(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		mock  *Mock
+		cmt   *Comment
+		exp   string
+	}{
+		{
+			title: "body",
+			mock:  &Mock{},
+			cmt: &Comment{
+				PRNumber: 1,
+				Body:     "hello",
+			},
+			exp: "hello",
+		},
+		{
+			title: "json",
+			mock: &Mock{
+				OutputJSON: true,
+			},
+			cmt: &Comment{
+				Org:       "suzuki-shunsuke",
+				Repo:      "github-comment",
+				PRNumber:  1,
+				CommentID: 10,
+				Body:      "hello",
+			},
+			exp: `{
+  "org": "suzuki-shunsuke",
+  "repo": "github-comment",
+  "pr_number": 1,
+  "sha1": "",
+  "action": "update",
+  "comment_id": 10,
+  "body": "hello"
+}
+`,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			b, err := d.mock.output(d.cmt)
+			require.Nil(t, err)
+			require.Equal(t, d.exp, string(b))
+		})
+	}
+}
+
+func TestMock_PostReviewComment(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join(t.TempDir(), "comment.json")
+	mock := &Mock{
+		Stderr:     io.Discard,
+		Output:     output,
+		OutputJSON: true,
+	}
+	cmt := &Comment{
+		Org:      "suzuki-shunsuke",
+		Repo:     "github-comment",
+		PRNumber: 1,
+		Path:     "main.go",
+		Line:     10,
+		Body:     "hello",
+	}
+	require.Nil(t, mock.PostReviewComment(context.Background(), cmt))
+	require.Equal(t, "https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-dry-run", cmt.URL)
+	b, err := os.ReadFile(output)
+	require.Nil(t, err)
+	require.Equal(t, `{
+  "org": "suzuki-shunsuke",
+  "repo": "github-comment",
+  "pr_number": 1,
+  "sha1": "",
+  "action": "create",
+  "body": "hello",
+  "path": "main.go",
+  "line": 10
+}
+`, string(b))
+}
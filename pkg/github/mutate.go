@@ -7,7 +7,9 @@ import (
 	"github.com/shurcooL/githubv4"
 )
 
-func (client *Client) HideComment(ctx context.Context, nodeID string) error {
+// HideComment minimizes a comment and returns whether the comment is actually minimized.
+// GitHub may not minimize the comment even if the mutation succeeds, e.g. if the token doesn't have the permission.
+func (client *Client) HideComment(ctx context.Context, nodeID string) (bool, error) {
 	var m struct {
 		MinimizeComment struct {
 			MinimizedComment struct {
@@ -22,7 +24,7 @@ func (client *Client) HideComment(ctx context.Context, nodeID string) error {
 		SubjectID:  nodeID,
 	}
 	if err := client.ghV4.Mutate(ctx, &m, input, nil); err != nil {
-		return fmt.Errorf("hide an old comment: %w", err)
+		return false, fmt.Errorf("hide an old comment: %w", err)
 	}
-	return nil
+	return bool(m.MinimizeComment.MinimizedComment.IsMinimized), nil
 }
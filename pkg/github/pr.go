@@ -24,3 +24,24 @@ func (client *Client) PRNumberWithSHA(ctx context.Context, owner, repo, sha stri
 	}
 	return prs[0].GetNumber(), nil
 }
+
+// PRInfo is the size and the base branch of the pull request.
+type PRInfo struct {
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	BaseRef      string
+}
+
+func (client *Client) PRInfo(ctx context.Context, owner, repo string, number int) (*PRInfo, error) {
+	pr, _, err := client.pr.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("get a pull request: %w", err)
+	}
+	return &PRInfo{
+		Additions:    pr.GetAdditions(),
+		Deletions:    pr.GetDeletions(),
+		ChangedFiles: pr.GetChangedFiles(),
+		BaseRef:      pr.GetBase().GetRef(),
+	}, nil
+}
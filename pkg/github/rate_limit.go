@@ -0,0 +1,33 @@
+package github
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// rateLimit is the rate limit of GitHub GraphQL API.
+// It's queried with other fields to report the cost of the query.
+// https://docs.github.com/en/graphql/overview/resource-limitations
+type rateLimit struct {
+	Cost      int
+	Remaining int
+}
+
+// recordRateLimit accumulates the cost of GraphQL queries and outputs it at debug level.
+func (client *Client) recordRateLimit(query string, limit rateLimit) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.graphQLCost += limit.Cost
+	logrus.WithFields(logrus.Fields{
+		"query":      query,
+		"cost":       limit.Cost,
+		"remaining":  limit.Remaining,
+		"total_cost": client.graphQLCost,
+	}).Debug("GitHub GraphQL API rate limit")
+}
+
+// GraphQLCost returns the total cost of GraphQL queries which the client has sent.
+func (client *Client) GraphQLCost() int {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.graphQLCost
+}
@@ -0,0 +1,14 @@
+package github
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddReaction adds a reaction to an issue or pull request comment.
+func (client *Client) AddReaction(ctx context.Context, org, repo string, commentID int64, content string) error {
+	if _, _, err := client.reaction.CreateIssueCommentReaction(ctx, org, repo, commentID, content); err != nil {
+		return fmt.Errorf("add a reaction to an issue or pull request comment by GitHub API: %w", err)
+	}
+	return nil
+}
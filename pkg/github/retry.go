@@ -0,0 +1,147 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the default max number of attempts of an API call
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialInterval is the default interval before the first retry
+	DefaultRetryInitialInterval = time.Second
+	// maxRetryWait is the max time to wait before a retry.
+	// If GitHub requests to wait longer, the request isn't retried
+	maxRetryWait = time.Minute
+)
+
+// retryTransport retries requests with exponential backoff and jitter on 5xx errors and rate limits.
+// If the response has the header Retry-After, it's honored.
+type retryTransport struct {
+	base            http.RoundTripper
+	maxAttempts     int
+	initialInterval time.Duration
+	sleep           func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, maxAttempts int, initialInterval time.Duration) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:            base,
+		maxAttempts:     maxAttempts,
+		initialInterval: initialInterval,
+		sleep:           sleep,
+	}
+}
+
+// NewRetryTransport returns a http.RoundTripper which retries requests on 5xx errors and rate limits.
+// It's also used to call GitLab API.
+func NewRetryTransport(base http.RoundTripper, maxAttempts int, initialInterval time.Duration) http.RoundTripper {
+	return newRetryTransport(base, maxAttempts, initialInterval)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= t.maxAttempts || !isRetryableResponse(resp) {
+			return resp, err //nolint:wrapcheck
+		}
+		wait, ok := t.waitTime(resp, attempt)
+		if !ok {
+			return resp, nil
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				// the request body can't be sent again
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil //nolint:nilerr
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		logrus.WithFields(logrus.Fields{
+			"method":      req.Method,
+			"url":         req.URL.String(),
+			"status_code": resp.StatusCode,
+			"attempt":     attempt,
+			"wait":        wait,
+		}).Warn("retry an API call")
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryableResponse returns true if the response is a 5xx error or a rate limit error.
+// Other 4xx errors aren't retried.
+func isRetryableResponse(resp *http.Response) bool {
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	// a secondary rate limit error may not have the header Retry-After
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(b)), "secondary rate limit")
+}
+
+// waitTime returns the time to wait before the next attempt.
+// If the time is too long, the second returned value is false.
+func (t *retryTransport) waitTime(resp *http.Response, attempt int) (time.Duration, bool) {
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if sec, err := strconv.Atoi(s); err == nil {
+			wait := time.Duration(sec) * time.Second
+			return wait, wait <= maxRetryWait
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Until(time.Unix(reset, 0))
+			if wait < 0 {
+				wait = 0
+			}
+			return wait, wait <= maxRetryWait
+		}
+	}
+	backoff := t.initialInterval << (attempt - 1)
+	// add jitter so that the wait is between the half and the whole of the backoff
+	if half := int64(backoff / 2); half > 0 { //nolint:gomnd
+		backoff = time.Duration(half + rand.Int63n(half+1)) //nolint:gosec
+	}
+	return backoff, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
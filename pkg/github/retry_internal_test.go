@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newResponse(statusCode int, header map[string]string, body string) *http.Response {
+	h := http.Header{}
+	for k, v := range header {
+		h.Set(k, v)
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     h,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestRetryTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		responses []*http.Response
+		expCode   int
+		expCalls  int
+	}{
+		{
+			title: "success",
+			responses: []*http.Response{
+				newResponse(http.StatusOK, nil, ""),
+			},
+			expCode:  http.StatusOK,
+			expCalls: 1,
+		},
+		{
+			title: "retry 5xx",
+			responses: []*http.Response{
+				newResponse(http.StatusBadGateway, nil, ""),
+				newResponse(http.StatusOK, nil, ""),
+			},
+			expCode:  http.StatusOK,
+			expCalls: 2,
+		},
+		{
+			title: "retry a secondary rate limit",
+			responses: []*http.Response{
+				newResponse(http.StatusForbidden, nil, `{"message": "You have exceeded a secondary rate limit."}`),
+				newResponse(http.StatusOK, nil, ""),
+			},
+			expCode:  http.StatusOK,
+			expCalls: 2,
+		},
+		{
+			title: "honor Retry-After",
+			responses: []*http.Response{
+				newResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "1"}, ""),
+				newResponse(http.StatusOK, nil, ""),
+			},
+			expCode:  http.StatusOK,
+			expCalls: 2,
+		},
+		{
+			title: "don't retry other 4xx",
+			responses: []*http.Response{
+				newResponse(http.StatusNotFound, nil, ""),
+			},
+			expCode:  http.StatusNotFound,
+			expCalls: 1,
+		},
+		{
+			title: "give up after max attempts",
+			responses: []*http.Response{
+				newResponse(http.StatusBadGateway, nil, ""),
+				newResponse(http.StatusBadGateway, nil, ""),
+				newResponse(http.StatusBadGateway, nil, ""),
+			},
+			expCode:  http.StatusBadGateway,
+			expCalls: 3,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			calls := 0
+			transport := newRetryTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				resp := d.responses[calls]
+				calls++
+				return resp, nil
+			}), 3, time.Millisecond) //nolint:gomnd
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				return nil
+			}
+			req, err := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader("{}"))
+			require.Nil(t, err)
+			resp, err := transport.RoundTrip(req)
+			require.Nil(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, d.expCode, resp.StatusCode)
+			require.Equal(t, d.expCalls, calls)
+		})
+	}
+}
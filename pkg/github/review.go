@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/sirupsen/logrus"
+)
+
+// Suggestion is a suggested change on specific lines of a file.
+// Line is the first line of the original lines.
+// If Original has multiple lines, the suggestion covers the lines from Line.
+type Suggestion struct {
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+}
+
+// lines returns the first and last line numbers which the suggestion covers.
+func (s *Suggestion) lines() (int, int) {
+	n := strings.Count(strings.TrimSuffix(s.Original, "\n"), "\n")
+	return s.Line, s.Line + n
+}
+
+// SuggestionBody returns the review comment body with a suggestion block.
+func (s *Suggestion) SuggestionBody() string {
+	fence := "```"
+	for strings.Contains(s.Replacement, fence) {
+		fence += "`"
+	}
+	return fence + "suggestion\n" + strings.TrimSuffix(s.Replacement, "\n") + "\n" + fence
+}
+
+// draftReviewComment maps the suggestion into a review comment on the right side of the diff.
+func (s *Suggestion) draftReviewComment() *github.DraftReviewComment {
+	start, end := s.lines()
+	cmt := &github.DraftReviewComment{
+		Path: github.String(s.File),
+		Body: github.String(s.SuggestionBody()),
+		Side: github.String("RIGHT"),
+		Line: github.Int(end),
+	}
+	if start != end {
+		cmt.StartLine = github.Int(start)
+		cmt.StartSide = github.String("RIGHT")
+	}
+	return cmt
+}
+
+// PostReviewComment posts the comment as a review comment anchored to cmt.Path and cmt.Line.
+// The comment is submitted as a review with a single comment on the right side of the diff.
+func (client *Client) PostReviewComment(ctx context.Context, cmt *Comment) error {
+	if cmt.PRNumber == 0 {
+		return errors.New("a review comment can be posted only to a pull request")
+	}
+	body := cmt.Body
+	if CommentLength(body) > MaxCommentLength {
+		body = cmt.BodyForTooLong
+	}
+	review := &github.PullRequestReviewRequest{
+		Event: github.String("COMMENT"),
+		Comments: []*github.DraftReviewComment{
+			{
+				Path: github.String(cmt.Path),
+				Body: github.String(body),
+				Side: github.String("RIGHT"),
+				Line: github.Int(cmt.Line),
+			},
+		},
+	}
+	if cmt.SHA1 != "" {
+		review.CommitID = github.String(cmt.SHA1)
+	}
+	r, _, err := client.pr.CreateReview(ctx, cmt.Org, cmt.Repo, cmt.PRNumber, review)
+	if err != nil {
+		return fmt.Errorf("create a review comment by GitHub API: %w", err)
+	}
+	cmt.URL = r.GetHTMLURL()
+	if !cmt.MinimizeOnCreate {
+		return nil
+	}
+	// the node id of the review can't be minimized, so the node id of the review comment is got
+	// the review has already been posted, so the error is only logged and the comment isn't minimized
+	comments, _, err := client.pr.ListReviewComments(ctx, cmt.Org, cmt.Repo, cmt.PRNumber, r.GetID(), nil)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"review_id": r.GetID(),
+		}).Warn("list comments of the review to minimize the review comment")
+		return nil
+	}
+	if len(comments) != 0 {
+		cmt.NodeID = comments[0].GetNodeID()
+		cmt.URL = comments[0].GetHTMLURL()
+	}
+	return nil
+}
+
+// CreateSuggestionReview submits suggestions as a single review.
+func (client *Client) CreateSuggestionReview(ctx context.Context, org, repo string, prNumber int, body string, suggestions []*Suggestion) error {
+	comments := make([]*github.DraftReviewComment, len(suggestions))
+	for i, s := range suggestions {
+		comments[i] = s.draftReviewComment()
+	}
+	review := &github.PullRequestReviewRequest{
+		Event:    github.String("COMMENT"),
+		Comments: comments,
+	}
+	if body != "" {
+		review.Body = github.String(body)
+	}
+	if _, _, err := client.pr.CreateReview(ctx, org, repo, prNumber, review); err != nil {
+		return fmt.Errorf("create a pull request review by GitHub API: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestion_SuggestionBody(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title      string
+		suggestion *Suggestion
+		exp        string
+	}{
+		{
+			title: "normal",
+			suggestion: &Suggestion{
+				Replacement: "foo\n",
+			},
+			exp: "```suggestion\nfoo\n```",
+		},
+		{
+			title: "replacement includes a code fence",
+			suggestion: &Suggestion{
+				Replacement: "```\nfoo\n```",
+			},
+			exp: "````suggestion\n```\nfoo\n```\n````",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, d.suggestion.SuggestionBody())
+		})
+	}
+}
+
+func TestSuggestion_lines(t *testing.T) {
+	t.Parallel()
+	s := &Suggestion{
+		Line:     10,
+		Original: "a\nb\nc\n",
+	}
+	start, end := s.lines()
+	require.Equal(t, 10, start)
+	require.Equal(t, 12, end)
+}
+
+type fakePullRequests struct {
+	PullRequestsService
+	reviewComments []*github.PullRequestComment
+	listCalls      int
+}
+
+func (f *fakePullRequests) CreateReview(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	return &github.PullRequestReview{
+		ID:      github.Int64(1),
+		HTMLURL: github.String("https://github.com/suzuki-shunsuke/github-comment/pull/1#pullrequestreview-1"),
+	}, nil, nil
+}
+
+func (f *fakePullRequests) ListReviewComments(ctx context.Context, owner, repo string, number int, reviewID int64, opts *github.ListOptions) ([]*github.PullRequestComment, *github.Response, error) {
+	f.listCalls++
+	return f.reviewComments, nil, nil
+}
+
+func TestClient_PostReviewComment(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title            string
+		minimizeOnCreate bool
+		expNodeID        string
+		expURL           string
+		expListCalls     int
+	}{
+		{
+			title:        "the review comment isn't listed if it isn't minimized",
+			expURL:       "https://github.com/suzuki-shunsuke/github-comment/pull/1#pullrequestreview-1",
+			expListCalls: 0,
+		},
+		{
+			title:            "the node id of the review comment is set to minimize it",
+			minimizeOnCreate: true,
+			expNodeID:        "PRRC_1",
+			expURL:           "https://github.com/suzuki-shunsuke/github-comment/pull/1#discussion_r1",
+			expListCalls:     1,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			pr := &fakePullRequests{
+				reviewComments: []*github.PullRequestComment{
+					{
+						NodeID:  github.String("PRRC_1"),
+						HTMLURL: github.String("https://github.com/suzuki-shunsuke/github-comment/pull/1#discussion_r1"),
+					},
+				},
+			}
+			client := &Client{pr: pr}
+			cmt := &Comment{
+				Org:              "suzuki-shunsuke",
+				Repo:             "github-comment",
+				PRNumber:         1,
+				Path:             "main.go",
+				Line:             10,
+				Body:             "hello",
+				MinimizeOnCreate: d.minimizeOnCreate,
+			}
+			require.Nil(t, client.PostReviewComment(context.Background(), cmt))
+			require.Equal(t, d.expNodeID, cmt.NodeID)
+			require.Equal(t, d.expURL, cmt.URL)
+			require.Equal(t, d.expListCalls, pr.listCalls)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// ReviewThread is a review thread of a pull request.
+type ReviewThread struct {
+	ID         string
+	IsResolved bool
+	IsOutdated bool
+	Comments   struct {
+		Nodes []*IssueComment
+	} `graphql:"comments(first: 100)"`
+}
+
+// ListReviewThreads lists review threads of a pull request.
+func (client *Client) ListReviewThreads(ctx context.Context, pr *PullRequest) ([]*ReviewThread, error) {
+	// https://github.com/shurcooL/githubv4#pagination
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes    []*ReviewThread
+					PageInfo struct {
+						EndCursor   githubv4.String
+						HasNextPage bool
+					}
+				} `graphql:"reviewThreads(first: 100, after: $threadsCursor)"` // 100 per page.
+			} `graphql:"pullRequest(number: $issueNumber)"`
+		} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+		RateLimit rateLimit
+	}
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(pr.Org),
+		"repositoryName":  githubv4.String(pr.Repo),
+		"issueNumber":     githubv4.Int(pr.PRNumber),
+		"threadsCursor":   (*githubv4.String)(nil), // Null after argument to get first page.
+	}
+
+	var allThreads []*ReviewThread
+	for {
+		if err := client.ghV4.Query(ctx, &q, variables); err != nil {
+			return nil, fmt.Errorf("list review threads by GitHub API: %w", err)
+		}
+		client.recordRateLimit("list review threads", q.RateLimit)
+		allThreads = append(allThreads, q.Repository.PullRequest.ReviewThreads.Nodes...)
+		if !q.Repository.PullRequest.ReviewThreads.PageInfo.HasNextPage {
+			break
+		}
+		variables["threadsCursor"] = githubv4.NewString(q.Repository.PullRequest.ReviewThreads.PageInfo.EndCursor)
+	}
+	return allThreads, nil
+}
+
+// ResolveReviewThread resolves a review thread.
+func (client *Client) ResolveReviewThread(ctx context.Context, threadID string) error {
+	var m struct {
+		ResolveReviewThread struct {
+			Thread struct {
+				IsResolved githubv4.Boolean
+			}
+		} `graphql:"resolveReviewThread(input:$input)"`
+	}
+	input := githubv4.ResolveReviewThreadInput{
+		ThreadID: threadID,
+	}
+	if err := client.ghV4.Mutate(ctx, &m, input, nil); err != nil {
+		return fmt.Errorf("resolve a review thread: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v49/github"
+)
+
+func (client *Client) CreateStatus(ctx context.Context, org, repo, sha, state, statusContext, description, targetURL string) error {
+	status := &github.RepoStatus{
+		State:   github.String(state),
+		Context: github.String(statusContext),
+	}
+	if description != "" {
+		status.Description = github.String(description)
+	}
+	if targetURL != "" {
+		status.TargetURL = github.String(targetURL)
+	}
+	if _, _, err := client.repo.CreateStatus(ctx, org, repo, sha, status); err != nil {
+		return fmt.Errorf("create a commit status by GitHub API: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func (client *Client) TeamExists(ctx context.Context, org, team string) (bool, error) {
+	_, resp, err := client.team.GetTeamBySlug(ctx, org, team)
+	if err == nil {
+		return true, nil
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("get a team by GitHub API: %w", err)
+}
@@ -3,12 +3,33 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 )
 
+// GetAuthenticatedUser returns the login of the authenticated user.
+// The login is fetched once and cached because it doesn't change in a process.
+// Errors aren't cached so that the lookup is retried.
 func (client *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	client.loginMutex.Lock()
+	defer client.loginMutex.Unlock()
+	if client.login != "" {
+		return client.login, nil
+	}
 	user, _, err := client.user.Get(ctx, "")
 	if err != nil {
 		return "", fmt.Errorf("get an authenticated user by GitHub API: %w", err)
 	}
-	return user.GetLogin(), nil
+	client.login = user.GetLogin()
+	return client.login, nil
+}
+
+func (client *Client) UserExists(ctx context.Context, login string) (bool, error) {
+	_, resp, err := client.user.Get(ctx, login)
+	if err == nil {
+		return true, nil
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("get a user by GitHub API: %w", err)
 }
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsers struct {
+	calls int
+}
+
+func (f *fakeUsers) Get(ctx context.Context, user string) (*github.User, *github.Response, error) {
+	f.calls++
+	return &github.User{
+		Login: github.String("octocat"),
+	}, nil, nil
+}
+
+func TestClient_GetAuthenticatedUser(t *testing.T) {
+	t.Parallel()
+	users := &fakeUsers{}
+	client := &Client{
+		user: users,
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		login, err := client.GetAuthenticatedUser(ctx)
+		require.Nil(t, err)
+		require.Equal(t, "octocat", login)
+	}
+	require.Equal(t, 1, users.calls)
+}
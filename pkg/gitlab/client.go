@@ -0,0 +1,132 @@
+// Package gitlab is a client of GitLab REST API v4.
+// It implements the same operations as the GitHub client against merge requests,
+// so that the same templates and metadata work on GitLab.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBaseURL is the base URL of GitLab.com REST API v4.
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// perPage is the page size of list APIs. It's the max page size of GitLab REST API
+const perPage = 100
+
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	// notes are merge request notes which have been listed or created.
+	// GitLab APIs to edit notes require the merge request iid, but some operations are given only the note id
+	notes map[int64]*note
+	mutex sync.Mutex
+}
+
+type ParamNew struct {
+	Token string
+	// BaseURL is the base URL of GitLab REST API v4 such as https://gitlab.example.com/api/v4.
+	// If it's empty, DefaultBaseURL is used
+	BaseURL string
+	// HTTPClient is used to call the API. If it's nil, http.DefaultClient is used
+	HTTPClient *http.Client
+}
+
+func New(param *ParamNew) *Client {
+	client := &Client{
+		httpClient: param.HTTPClient,
+		baseURL:    strings.TrimSuffix(param.BaseURL, "/"),
+		token:      param.Token,
+		notes:      map[int64]*note{},
+	}
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+	if client.baseURL == "" {
+		client.baseURL = DefaultBaseURL
+	}
+	return client
+}
+
+// apiError is returned if GitLab API returns a status code other than 2xx.
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("GitLab API returned the status code %d: %s", e.StatusCode, e.Body)
+}
+
+// projectPath returns the URL path of the project. The project is identified by the URL encoded full path.
+// org can include subgroups such as "group/subgroup".
+func projectPath(org, repo string) string {
+	return "/projects/" + url.PathEscape(org+"/"+repo)
+}
+
+// webURL returns the base URL of the web UI, which is the API base URL without "/api/v4".
+func (client *Client) webURL() string {
+	return strings.TrimSuffix(client.baseURL, "/api/v4")
+}
+
+// do calls the API and decodes the response body into out if out isn't nil.
+// The response is returned so that the pagination header can be read.
+func (client *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*http.Response, error) {
+	u := client.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal a request body as JSON: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", client.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send a request to GitLab API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 { //nolint:gomnd
+		b, _ := io.ReadAll(resp.Body)
+		return resp, &apiError{
+			StatusCode: resp.StatusCode,
+			Body:       string(b),
+		}
+	}
+	if out == nil {
+		return resp, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("decode a response body as JSON: %w", err)
+	}
+	return resp, nil
+}
+
+// nextPage returns the next page number. If there is no next page, 0 is returned.
+func nextPage(resp *http.Response) int {
+	n, err := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
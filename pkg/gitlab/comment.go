@@ -0,0 +1,215 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+// note is a comment of a merge request.
+type note struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+
+	org   string
+	repo  string
+	mrIID int
+}
+
+// hiddenPrefix is prepended to the body of hidden notes.
+// GitLab doesn't support minimizing notes, so the body is collapsed instead.
+const hiddenPrefix = "<details><summary>This comment is outdated</summary>\n\n"
+
+const hiddenSuffix = "\n\n</details>"
+
+// MaxCommentLength is the max length of a note body.
+// It's same as GitHub so that comments are truncated in the same way.
+const MaxCommentLength = github.MaxCommentLength
+
+func (client *Client) rememberNote(n *note) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.notes[n.ID] = n
+}
+
+func (client *Client) getNote(id int64) (*note, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	n, ok := client.notes[id]
+	if !ok {
+		return nil, fmt.Errorf("the merge request of the note %d is unknown", id)
+	}
+	return n, nil
+}
+
+// CreateComment creates or updates a merge request note.
+// If cmt.PRNumber is 0, a comment is created on the commit instead.
+func (client *Client) CreateComment(ctx context.Context, cmt *github.Comment) error {
+	body := cmt.Body
+	if github.CommentLength(body) > MaxCommentLength {
+		body = cmt.BodyForTooLong
+	}
+	if cmt.PRNumber == 0 {
+		return client.createCommitComment(ctx, cmt, body)
+	}
+	path := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(cmt.Org, cmt.Repo), cmt.PRNumber)
+	method := http.MethodPost
+	if cmt.CommentID != 0 {
+		path += "/" + strconv.FormatInt(cmt.CommentID, 10)
+		method = http.MethodPut
+	}
+	n := &note{}
+	if _, err := client.do(ctx, method, path, nil, map[string]string{
+		"body": body,
+	}, n); err != nil {
+		return fmt.Errorf("create or update a merge request note by GitLab API: %w", err)
+	}
+	n.org = cmt.Org
+	n.repo = cmt.Repo
+	n.mrIID = cmt.PRNumber
+	client.rememberNote(n)
+	cmt.NodeID = strconv.FormatInt(n.ID, 10)
+	cmt.URL = fmt.Sprintf("%s/%s/%s/-/merge_requests/%d#note_%d", client.webURL(), cmt.Org, cmt.Repo, cmt.PRNumber, n.ID)
+	return nil
+}
+
+func (client *Client) createCommitComment(ctx context.Context, cmt *github.Comment, body string) error {
+	if cmt.CommentID != 0 {
+		return errors.New("GitLab doesn't support updating a commit comment")
+	}
+	path := fmt.Sprintf("%s/repository/commits/%s/comments", projectPath(cmt.Org, cmt.Repo), url.PathEscape(cmt.SHA1))
+	if _, err := client.do(ctx, http.MethodPost, path, nil, map[string]string{
+		"note": body,
+	}, nil); err != nil {
+		return fmt.Errorf("create a commit comment by GitLab API: %w", err)
+	}
+	cmt.URL = fmt.Sprintf("%s/%s/%s/-/commit/%s", client.webURL(), cmt.Org, cmt.Repo, cmt.SHA1)
+	return nil
+}
+
+// ListComments lists notes of the merge request in the ascending order of creation.
+// System notes such as "added 1 commit" are excluded.
+func (client *Client) ListComments(ctx context.Context, pr *github.PullRequest) ([]*github.IssueComment, error) {
+	path := fmt.Sprintf("%s/merge_requests/%d/notes", projectPath(pr.Org, pr.Repo), pr.PRNumber)
+	query := url.Values{
+		"sort":     []string{"asc"},
+		"order_by": []string{"created_at"},
+		"per_page": []string{strconv.Itoa(perPage)},
+	}
+	var comments []*github.IssueComment
+	for page := 1; page != 0; {
+		query.Set("page", strconv.Itoa(page))
+		var notes []*note
+		resp, err := client.do(ctx, http.MethodGet, path, query, nil, &notes)
+		if err != nil {
+			return nil, fmt.Errorf("list merge request notes by GitLab API: %w", err)
+		}
+		for _, n := range notes {
+			if n.System {
+				continue
+			}
+			n.org = pr.Org
+			n.repo = pr.Repo
+			n.mrIID = pr.PRNumber
+			client.rememberNote(n)
+			cmt := &github.IssueComment{
+				ID:         strconv.FormatInt(n.ID, 10),
+				DatabaseID: n.ID,
+				Body:       n.Body,
+				CreatedAt:  n.CreatedAt,
+				UpdatedAt:  n.UpdatedAt,
+				// whether the note can be edited is judged by the author
+				ViewerCanMinimize: true,
+				IsMinimized:       strings.HasPrefix(n.Body, hiddenPrefix),
+			}
+			cmt.Author.Login = n.Author.Username
+			comments = append(comments, cmt)
+		}
+		page = nextPage(resp)
+	}
+	return comments, nil
+}
+
+// HideComment collapses the note because GitLab doesn't support minimizing notes.
+// The note must be listed by ListComments or created by CreateComment in advance.
+func (client *Client) HideComment(ctx context.Context, nodeID string) (bool, error) {
+	id, err := strconv.ParseInt(nodeID, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("parse a note id: %w", err)
+	}
+	n, err := client.getNote(id)
+	if err != nil {
+		return false, err
+	}
+	if strings.HasPrefix(n.Body, hiddenPrefix) {
+		return true, nil
+	}
+	if err := client.CreateComment(ctx, &github.Comment{
+		Org:       n.org,
+		Repo:      n.repo,
+		PRNumber:  n.mrIID,
+		CommentID: n.ID,
+		Body:      hiddenPrefix + n.Body + hiddenSuffix,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteComment deletes the note.
+// The note must be listed by ListComments or created by CreateComment in advance.
+func (client *Client) DeleteComment(ctx context.Context, org, repo string, commentID int64) error {
+	n, err := client.getNote(commentID)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s/merge_requests/%d/notes/%d", projectPath(org, repo), n.mrIID, commentID)
+	if _, err := client.do(ctx, http.MethodDelete, path, nil, nil, nil); err != nil {
+		return fmt.Errorf("delete a merge request note by GitLab API: %w", err)
+	}
+	return nil
+}
+
+// emojiNames maps GitHub reaction contents to GitLab award emoji names.
+var emojiNames = map[string]string{ //nolint:gochecknoglobals
+	"+1":       "thumbsup",
+	"-1":       "thumbsdown",
+	"laugh":    "laughing",
+	"confused": "confused",
+	"heart":    "heart",
+	"hooray":   "tada",
+	"rocket":   "rocket",
+	"eyes":     "eyes",
+}
+
+// AddReaction awards an emoji to the note.
+// The note must be listed by ListComments or created by CreateComment in advance.
+func (client *Client) AddReaction(ctx context.Context, org, repo string, commentID int64, content string) error {
+	name, ok := emojiNames[content]
+	if !ok {
+		return fmt.Errorf("the reaction %s isn't supported", content)
+	}
+	n, err := client.getNote(commentID)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s/merge_requests/%d/notes/%d/award_emoji", projectPath(org, repo), n.mrIID, commentID)
+	if _, err := client.do(ctx, http.MethodPost, path, nil, map[string]string{
+		"name": name,
+	}, nil); err != nil {
+		return fmt.Errorf("award an emoji to a merge request note by GitLab API: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+func TestClient_ListComments(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/group/sub/app/merge_requests/3/notes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "xxx" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = w.Write([]byte(`[{"id": 1, "body": "hello", "author": {"username": "bot"}, "created_at": "2023-01-01T00:00:00Z"}, {"id": 2, "body": "added 1 commit", "system": true}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id": 3, "body": "<details><summary>This comment is outdated</summary>\n\nfoo\n\n</details>", "author": {"username": "bot"}}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := New(&ParamNew{
+		Token:   "xxx",
+		BaseURL: server.URL + "/api/v4",
+	})
+	comments, err := client.ListComments(context.Background(), &github.PullRequest{
+		Org:      "group/sub",
+		Repo:     "app",
+		PRNumber: 3,
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, len(comments))
+	require.Equal(t, "1", comments[0].ID)
+	require.Equal(t, "bot", comments[0].Author.Login)
+	require.False(t, comments[0].IsMinimized)
+	require.True(t, comments[1].IsMinimized)
+}
+
+func TestClient_HideComment(t *testing.T) {
+	t.Parallel()
+	var body string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/org/repo/merge_requests/3/notes/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		m := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = m["body"]
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := New(&ParamNew{
+		Token:   "xxx",
+		BaseURL: server.URL + "/api/v4",
+	})
+	ctx := context.Background()
+	_, err := client.HideComment(ctx, "1")
+	require.NotNil(t, err, "the merge request of the note is unknown")
+
+	client.rememberNote(&note{
+		ID:    1,
+		Body:  "hello",
+		org:   "org",
+		repo:  "repo",
+		mrIID: 3,
+	})
+	hidden, err := client.HideComment(ctx, "1")
+	require.Nil(t, err)
+	require.True(t, hidden)
+	require.Equal(t, hiddenPrefix+"hello"+hiddenSuffix, body)
+}
@@ -0,0 +1,121 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+type mergeRequest struct {
+	IID          int    `json:"iid"`
+	State        string `json:"state"`
+	TargetBranch string `json:"target_branch"`
+	// ChangesCount is a string because it can be "1000+"
+	ChangesCount string `json:"changes_count"`
+}
+
+// PRNumberWithSHA returns the iid of the merge request associated with the commit.
+// An open merge request takes precedence.
+func (client *Client) PRNumberWithSHA(ctx context.Context, owner, repo, sha string) (int, error) {
+	var mrs []*mergeRequest
+	path := fmt.Sprintf("%s/repository/commits/%s/merge_requests", projectPath(owner, repo), url.PathEscape(sha))
+	if _, err := client.do(ctx, http.MethodGet, path, nil, nil, &mrs); err != nil {
+		return 0, fmt.Errorf("list associated merge requests by GitLab API: %w", err)
+	}
+	if len(mrs) == 0 {
+		return 0, errors.New("associated merge request isn't found")
+	}
+	for _, mr := range mrs {
+		if mr.State == "opened" {
+			return mr.IID, nil
+		}
+	}
+	return mrs[0].IID, nil
+}
+
+// PRInfo returns the base branch and the number of changed files of the merge request.
+// GitLab doesn't return the numbers of additions and deletions, so they are 0.
+func (client *Client) PRInfo(ctx context.Context, owner, repo string, number int) (*github.PRInfo, error) {
+	mr := &mergeRequest{}
+	path := fmt.Sprintf("%s/merge_requests/%d", projectPath(owner, repo), number)
+	if _, err := client.do(ctx, http.MethodGet, path, nil, nil, mr); err != nil {
+		return nil, fmt.Errorf("get a merge request by GitLab API: %w", err)
+	}
+	// changes_count is "1000+" if there are too many changes, then it's treated as 0
+	changedFiles, _ := strconv.Atoi(mr.ChangesCount)
+	return &github.PRInfo{
+		ChangedFiles: changedFiles,
+		BaseRef:      mr.TargetBranch,
+	}, nil
+}
+
+func (client *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	user := &struct {
+		Username string `json:"username"`
+	}{}
+	if _, err := client.do(ctx, http.MethodGet, "/user", nil, nil, user); err != nil {
+		return "", fmt.Errorf("get an authenticated user by GitLab API: %w", err)
+	}
+	return user.Username, nil
+}
+
+// TeamExists returns true if the subgroup org/team exists.
+func (client *Client) TeamExists(ctx context.Context, org, team string) (bool, error) {
+	_, err := client.do(ctx, http.MethodGet, "/groups/"+url.PathEscape(org+"/"+team), nil, nil, nil)
+	if err == nil {
+		return true, nil
+	}
+	var e *apiError
+	if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("get a group by GitLab API: %w", err)
+}
+
+func (client *Client) UserExists(ctx context.Context, login string) (bool, error) {
+	var users []struct {
+		ID int64 `json:"id"`
+	}
+	if _, err := client.do(ctx, http.MethodGet, "/users", url.Values{
+		"username": []string{login},
+	}, nil, &users); err != nil {
+		return false, fmt.Errorf("get a user by GitLab API: %w", err)
+	}
+	return len(users) != 0, nil
+}
+
+// commitStatusStates maps GitHub commit status states to GitLab commit status states.
+var commitStatusStates = map[string]string{ //nolint:gochecknoglobals
+	"success": "success",
+	"failure": "failed",
+	"error":   "failed",
+	"pending": "pending",
+}
+
+// CreateStatus sets a commit status. statusContext is set as the name of the status.
+func (client *Client) CreateStatus(ctx context.Context, org, repo, sha, state, statusContext, description, targetURL string) error {
+	s, ok := commitStatusStates[state]
+	if !ok {
+		return fmt.Errorf("the commit status state %s isn't supported", state)
+	}
+	body := map[string]string{
+		"state": s,
+		"name":  statusContext,
+	}
+	if description != "" {
+		body["description"] = description
+	}
+	if targetURL != "" {
+		body["target_url"] = targetURL
+	}
+	path := fmt.Sprintf("%s/statuses/%s", projectPath(org, repo), url.PathEscape(sha))
+	if _, err := client.do(ctx, http.MethodPost, path, nil, body, nil); err != nil {
+		return fmt.Errorf("create a commit status by GitLab API: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/suzuki-shunsuke/github-comment/pkg/github"
+)
+
+var errUnsupported = errors.New("this operation isn't supported on GitLab")
+
+func (client *Client) ListReviewThreads(ctx context.Context, pr *github.PullRequest) ([]*github.ReviewThread, error) {
+	return nil, errUnsupported
+}
+
+func (client *Client) ResolveReviewThread(ctx context.Context, threadID string) error {
+	return errUnsupported
+}
+
+func (client *Client) CreateSuggestionReview(ctx context.Context, org, repo string, prNumber int, body string, suggestions []*github.Suggestion) error {
+	return errUnsupported
+}
+
+func (client *Client) PostReviewComment(ctx context.Context, cmt *github.Comment) error {
+	return errUnsupported
+}
@@ -2,12 +2,47 @@ package option
 
 import (
 	"errors"
+	"time"
 )
 
 type ExecOptions struct {
 	Options
-	Args        []string
+	Args []string
+	// CommandFile is a file path of the shell script which is run with sh -c.
+	// It can't be used with Args
+	CommandFile string
 	SkipComment bool
+	// StateFile is a file path where results of commands are saved.
+	// Results of previous runs are read from the file and exposed as .Previous in templates.
+	StateFile string
+	// Retry is the max number of times the command is retried until it succeeds.
+	// Only the result of the last run is commented
+	Retry      int
+	RetryDelay time.Duration
+	// Timeout bounds the whole run of the command including retries.
+	// If the command times out, it's killed and the comment is posted with the exit code 124
+	Timeout time.Duration
+	// FirstFailureOnly posts a comment only for the first failed command in a session.
+	// Whether a failure has been commented is tracked in SessionFile
+	FirstFailureOnly bool
+	SessionFile      string
+	// AlwaysComment disables FirstFailureOnly
+	AlwaysComment bool
+	// CollectMatchedConfigs exposes names of all matched exec configs as .MatchedConfigs in templates
+	CollectMatchedConfigs bool
+	// FailOnNoMatch makes exec fail if no exec config matches.
+	// If the command fails, the error of the command takes precedence
+	FailOnNoMatch bool
+	// ExtraTemplateKeys are template keys other than TemplateKey.
+	// A comment is posted per template key with the result of a single run
+	ExtraTemplateKeys []string
+	// MergeVarsFromComment merges the embedded Vars of the previous comment with the same template key and target
+	// into Vars with the lowest precedence
+	MergeVarsFromComment bool
+	// UpdateCondition updates the comment that matches with the condition.
+	// The update condition of the exec config takes precedence over it.
+	// commentExists in when is true if a comment matching it or default_update_condition exists
+	UpdateCondition string
 }
 
 func ValidateExec(opts *ExecOptions) error {
@@ -17,8 +52,20 @@ func ValidateExec(opts *ExecOptions) error {
 	if opts.TemplateKey == "" {
 		return errors.New("template-key is required")
 	}
+	if opts.Template != "" && len(opts.ExtraTemplateKeys) != 0 {
+		return errors.New("template and multiple template keys can't be used at the same time")
+	}
 	if len(opts.Args) == 0 {
 		return errors.New("command is required")
 	}
+	if opts.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if opts.Retry < 0 {
+		return errors.New("exec-retry must not be negative")
+	}
+	if opts.FirstFailureOnly && opts.SessionFile == "" {
+		return errors.New("session-file is required when comment-on-first-failure-only is set")
+	}
 	return nil
 }
@@ -9,6 +9,9 @@ type HideOptions struct {
 	HideKey       string
 	Condition     string
 	StdinTemplate bool
+	// ReasonTemplate is a template of the reason why comments are hidden.
+	// If it's set, "> folded: <reason>" is prepended to comments before they are hidden
+	ReasonTemplate string
 }
 
 func ValidateHide(opts *HideOptions) error {
@@ -0,0 +1,18 @@
+package option
+
+import (
+	"errors"
+)
+
+type ListOptions struct {
+	Options
+	// OutputFormat is text or json
+	OutputFormat string
+}
+
+func ValidateList(opts *ListOptions) error {
+	if opts.PRNumber <= 0 {
+		return errors.New("pull request number is required")
+	}
+	return validate(&opts.Options)
+}
@@ -2,6 +2,7 @@ package option
 
 import (
 	"errors"
+	"time"
 )
 
 type Options struct {
@@ -13,36 +14,158 @@ type Options struct {
 	Template           string
 	TemplateForTooLong string
 	TemplateKey        string
+	Target             string
 	ConfigPath         string
 	HideOldComment     string
 	LogLevel           string
+	Lang               string
 	Vars               map[string]string
 	EmbeddedVarNames   []string
 	DryRun             bool
 	SkipNoToken        bool
 	Silent             bool
+	// AppAuth is true if github-comment authenticates as a GitHub App installation. Then Token isn't required
+	AppAuth bool
+	// DryRunOutput is a file path where the rendered comment body is written in dry-run
+	DryRunOutput string
+	// DryRunOutputFormat is the format of DryRunOutput. body (default) or json
+	DryRunOutputFormat string
+	// ValidateMentions is how to handle mentions to users and teams which don't exist.
+	// Mentions in code blocks, code spans, and the command output of exec aren't validated.
+	// "" (default): mentions aren't validated
+	// warn: output warning logs
+	// fail: fail to post the comment
+	ValidateMentions string
+	// MetadataOut is a file path where the embedded metadata is written as JSON when a comment is posted
+	MetadataOut string
+	// NoMetadata disables the embedded metadata.
+	// Comments posted without metadata can't be found by update conditions and hide conditions afterward.
+	NoMetadata bool
+	// RequirePR makes the command fail if no pull request is found
+	RequirePR bool
+	// DebugFooter appends a collapsed section with the template key, the matched config, vars, and the run id
+	DebugFooter bool
+	// MinimizeOnCreate minimizes the comment immediately after the comment is posted
+	MinimizeOnCreate bool
+	// MatrixJSON is the matrix context of GitHub Actions as JSON. It's exposed as .Matrix
+	MatrixJSON string
+	// IdempotencyKey is embedded in the metadata.
+	// If a non-minimized comment with the same key already exists, the comment isn't posted
+	IdempotencyKey string
+	// PRCommentLimit is the max number of comments which github-comment posts to a pull request.
+	// If it's zero, max_comments_per_pr in the configuration file or the default value is used
+	PRCommentLimit int
+	// CooldownFile is a file path where times when comments were posted are recorded per template key and target.
+	// If a comment was posted within Cooldown, the comment isn't posted
+	CooldownFile string
+	Cooldown     time.Duration
+	// FooterFile is a file path of a footer template which is appended to comments.
+	// A relative path is resolved relative to the configuration file
+	FooterFile string
+	// EnvFile is a dotenv file path. The environment variables are referred in templates and expressions, and passed to the command of exec
+	EnvFile string
+	// Summary is the key of a summary comment. The link to the posted comment is recorded into the summary comment,
+	// which lists links to comments posted with the same key
+	Summary string
+	// StructuredVars are variables read from JSON and YAML files by --var-file-json and --var-file-yaml.
+	// Vars take precedence over them
+	StructuredVars map[string]interface{}
+	// TemplateKeyFromGit derives the template key from the current branch by template_key_rules in the configuration file
+	TemplateKeyFromGit bool
+	// PRBase is glob patterns of base branches. Comments are posted only on pull requests targeting the branches
+	PRBase []string
+	// AllowUndefinedVars leaves references ${name} to undefined variables in Vars as is instead of failing
+	AllowUndefinedVars bool
 }
 
-func validate(opts *Options) error {
+// ValidateRepository validates the repository where the comment is posted.
+// It should be called before GitHub API is called so that the error is clear.
+func ValidateRepository(opts *Options) error {
 	if opts.Org == "" {
-		return errors.New("org is required")
+		return errors.New("org is required. Please set it by the command line option --org, base.org in the configuration file, or CI built in environment variables such as GITHUB_REPOSITORY_OWNER")
 	}
 	if opts.Repo == "" {
-		return errors.New("repo is required")
+		return errors.New("repo is required. Please set it by the command line option --repo, base.repo in the configuration file, or CI built in environment variables such as GITHUB_REPOSITORY")
+	}
+	return nil
+}
+
+func validate(opts *Options) error {
+	if err := ValidateRepository(opts); err != nil {
+		return err
 	}
-	if opts.Token == "" && !opts.SkipNoToken {
+	if opts.Token == "" && !opts.SkipNoToken && !opts.AppAuth {
 		return errors.New("token is required")
 	}
 	if opts.SHA1 == "" && opts.PRNumber <= 0 {
-		return errors.New("sha1 or pr are required")
+		return errors.New("sha1 or pr are required. Please set them by the command line option --sha1 or --pr, or CI built in environment variables")
+	}
+	switch opts.ValidateMentions {
+	case "", ValidateMentionsWarn, ValidateMentionsFail:
+	default:
+		return errors.New(`validate-mentions must be either "warn" or "fail"`)
+	}
+	if opts.CooldownFile != "" && opts.Cooldown <= 0 {
+		return errors.New("comment-cooldown must be positive if comment-cooldown-file is set")
+	}
+	switch opts.DryRunOutputFormat {
+	case "", DryRunOutputFormatBody, DryRunOutputFormatJSON:
+	default:
+		return errors.New(`dry-run-output-format must be either "body" or "json"`)
 	}
 	return nil
 }
 
+const (
+	ValidateMentionsWarn = "warn"
+	ValidateMentionsFail = "fail"
+)
+
+const (
+	DryRunOutputFormatBody = "body"
+	DryRunOutputFormatJSON = "json"
+)
+
 type PostOptions struct {
 	Options
 	StdinTemplate   bool
 	UpdateCondition string
+	// EditWithin limits comments updated by UpdateCondition to ones created within the duration.
+	// If no comment is found, a new comment is created.
+	EditWithin time.Duration
+	// CommentIf is an expression. If it isn't matched, the comment isn't posted.
+	// commentExists is true if a comment matching UpdateCondition exists, so "!commentExists" creates a comment only if none exists
+	CommentIf string
+	// MergeVarsFromComment merges the embedded Vars of the updated comment into Vars with the lowest precedence
+	MergeVarsFromComment bool
+	// Append appends the body to the body of the updated comment with AppendDelimiter instead of replacing it.
+	// The embedded metadata of the updated comment is replaced with the new one.
+	// If the appended comment exceeds the max length, posting fails instead of falling back to template_for_too_long.
+	// Note that appends aren't atomic because the comment is read and then updated.
+	// If jobs such as matrix legs append to the same comment simultaneously, bodies of some of them can be lost.
+	// Serialize the jobs, for example, by a concurrency group of GitHub Actions
+	// or by a pull request label as a mutex: a job waits while the label exists,
+	// adds the label before appending, and removes it after appending.
+	Append          bool
+	AppendDelimiter string
+	// Repin deletes the updated comment and creates a new comment so that the comment moves to the bottom.
+	// If the comment was created within RepinCooldown, the comment is updated instead.
+	Repin         bool
+	RepinCooldown time.Duration
+	// MinInterval skips posting a comment if the comment matching UpdateCondition was updated within the duration
+	MinInterval time.Duration
+	// Sticky is the name of the sticky comment.
+	// The comment with the same name is updated if it exists, otherwise a new comment is created
+	Sticky string
+	// CommentID is the database id of the comment which is updated.
+	// If it's set, UpdateCondition is ignored and comments aren't listed
+	// to search the updated comment, check the idempotency key, and check the max number of comments.
+	// comment(target) in comment-if and the summary comment still list comments
+	CommentID int64
+	// ExtraMetadata is extra metadata embedded in the comment. Values are rendered as templates
+	ExtraMetadata map[string]string
+	// Once skips posting a comment if a comment matching UpdateCondition already exists, instead of updating it
+	Once bool
 }
 
 func ValidatePost(opts *PostOptions) error {
@@ -52,5 +175,16 @@ func ValidatePost(opts *PostOptions) error {
 	if opts.Template == "" && opts.TemplateKey == "" {
 		return errors.New("template or template-key are required")
 	}
+	if opts.Once && opts.CommentID != 0 {
+		return errors.New("once and comment-id can't be used at the same time")
+	}
+	if opts.Sticky != "" {
+		if opts.UpdateCondition != "" {
+			return errors.New("sticky and update-condition can't be used at the same time")
+		}
+		if opts.NoMetadata {
+			return errors.New("sticky and no-metadata can't be used at the same time")
+		}
+	}
 	return nil
 }
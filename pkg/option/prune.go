@@ -0,0 +1,19 @@
+package option
+
+import (
+	"errors"
+)
+
+type PruneOptions struct {
+	Options
+	// Author is the login of the user who posted comments to be pruned.
+	// The default is the authenticated user
+	Author string
+}
+
+func ValidatePrune(opts *PruneOptions) error {
+	if opts.PRNumber <= 0 {
+		return errors.New("pull request or issue number is required")
+	}
+	return validate(&opts.Options)
+}
@@ -0,0 +1,36 @@
+package option
+
+import (
+	"errors"
+)
+
+type ReactOptions struct {
+	Options
+	// Content is the reaction such as +1 and rocket
+	Content string
+	// UpdateCondition is the condition of the comment which the reaction is added to
+	UpdateCondition string
+}
+
+// reactionContents are reactions which GitHub supports.
+// https://docs.github.com/en/rest/reactions/reactions#about-reactions
+var reactionContents = map[string]struct{}{ //nolint:gochecknoglobals
+	"+1":       {},
+	"-1":       {},
+	"laugh":    {},
+	"confused": {},
+	"heart":    {},
+	"hooray":   {},
+	"rocket":   {},
+	"eyes":     {},
+}
+
+func ValidateReact(opts *ReactOptions) error {
+	if opts.PRNumber <= 0 {
+		return errors.New("pull request number is required")
+	}
+	if _, ok := reactionContents[opts.Content]; !ok {
+		return errors.New("content must be one of +1, -1, laugh, confused, heart, hooray, rocket, and eyes: " + opts.Content)
+	}
+	return validate(&opts.Options)
+}
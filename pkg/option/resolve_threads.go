@@ -0,0 +1,18 @@
+package option
+
+import (
+	"errors"
+)
+
+type ResolveThreadsOptions struct {
+	Options
+	// ResolveOutdated resolves unresolved outdated threads started by github-comment
+	ResolveOutdated bool
+}
+
+func ValidateResolveThreads(opts *ResolveThreadsOptions) error {
+	if opts.PRNumber <= 0 {
+		return errors.New("pull request number is required")
+	}
+	return validate(&opts.Options)
+}
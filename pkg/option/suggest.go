@@ -0,0 +1,23 @@
+package option
+
+import (
+	"errors"
+)
+
+type SuggestOptions struct {
+	Options
+	// SuggestionsFile is a path to the JSON file of suggestions. If it's "-", suggestions are read from the standard input
+	SuggestionsFile string
+	// Body is the review body
+	Body string
+}
+
+func ValidateSuggest(opts *SuggestOptions) error {
+	if opts.PRNumber <= 0 {
+		return errors.New("pull request number is required")
+	}
+	if opts.SuggestionsFile == "" {
+		return errors.New("suggestions is required")
+	}
+	return validate(&opts.Options)
+}
@@ -0,0 +1,93 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/go-ci-env/v3/cienv"
+)
+
+// BitbucketPipelines complements options with Bitbucket Pipelines' built-in environment variables.
+// This is useful for teams mirroring repositories to GitHub.
+type BitbucketPipelines struct {
+	getenv func(string) string
+}
+
+func NewBitbucketPipelines(param *cienv.Param) *BitbucketPipelines {
+	if param == nil || param.Getenv == nil {
+		return &BitbucketPipelines{
+			getenv: os.Getenv,
+		}
+	}
+	return &BitbucketPipelines{
+		getenv: param.Getenv,
+	}
+}
+
+func (bp *BitbucketPipelines) ID() string {
+	return "bitbucket-pipelines"
+}
+
+func (bp *BitbucketPipelines) Match() bool {
+	return bp.getenv("BITBUCKET_BUILD_NUMBER") != ""
+}
+
+func (bp *BitbucketPipelines) RepoOwner() string {
+	return bp.getenv("BITBUCKET_REPO_OWNER")
+}
+
+func (bp *BitbucketPipelines) RepoName() string {
+	return bp.getenv("BITBUCKET_REPO_SLUG")
+}
+
+func (bp *BitbucketPipelines) Ref() string {
+	if tag := bp.Tag(); tag != "" {
+		return "refs/tags/" + tag
+	}
+	if branch := bp.Branch(); branch != "" {
+		return "refs/heads/" + branch
+	}
+	return ""
+}
+
+func (bp *BitbucketPipelines) Tag() string {
+	return bp.getenv("BITBUCKET_TAG")
+}
+
+func (bp *BitbucketPipelines) Branch() string {
+	return bp.getenv("BITBUCKET_BRANCH")
+}
+
+func (bp *BitbucketPipelines) PRBaseBranch() string {
+	return bp.getenv("BITBUCKET_PR_DESTINATION_BRANCH")
+}
+
+func (bp *BitbucketPipelines) SHA() string {
+	return bp.getenv("BITBUCKET_COMMIT")
+}
+
+func (bp *BitbucketPipelines) IsPR() bool {
+	return bp.getenv("BITBUCKET_PR_ID") != ""
+}
+
+func (bp *BitbucketPipelines) PRNumber() (int, error) {
+	pr := bp.getenv("BITBUCKET_PR_ID")
+	if pr == "" {
+		return 0, nil
+	}
+	b, err := strconv.Atoi(pr)
+	if err == nil {
+		return b, nil
+	}
+	return 0, fmt.Errorf("BITBUCKET_PR_ID is invalid. It failed to parse BITBUCKET_PR_ID as an integer: %w", err)
+}
+
+func (bp *BitbucketPipelines) JobURL() string {
+	origin := strings.TrimSuffix(bp.getenv("BITBUCKET_GIT_HTTP_ORIGIN"), "/")
+	if origin == "" {
+		return ""
+	}
+	return origin + "/addon/pipelines/home#!/results/" + bp.getenv("BITBUCKET_BUILD_NUMBER")
+}
@@ -78,6 +78,20 @@ func (pt *Platform) complement(opts *option.Options) error {
 		}
 		opts.Repo = repo
 	}
+	if pt.CI() == "github-actions" {
+		run, err := getWorkflowRun()
+		if err != nil {
+			return err
+		}
+		if run != nil {
+			if opts.SHA1 == "" {
+				opts.SHA1 = run.HeadSHA
+			}
+			if opts.PRNumber <= 0 {
+				opts.PRNumber = run.PRNumber()
+			}
+		}
+	}
 	if opts.SHA1 == "" {
 		sha1, err := pt.getSHA1()
 		if err != nil {
@@ -105,6 +119,26 @@ func (pt *Platform) ComplementHide(opts *option.HideOptions) error {
 	return pt.complement(&opts.Options)
 }
 
+func (pt *Platform) ComplementPrune(opts *option.PruneOptions) error {
+	return pt.complement(&opts.Options)
+}
+
+func (pt *Platform) ComplementSuggest(opts *option.SuggestOptions) error {
+	return pt.complement(&opts.Options)
+}
+
+func (pt *Platform) ComplementResolveThreads(opts *option.ResolveThreadsOptions) error {
+	return pt.complement(&opts.Options)
+}
+
+func (pt *Platform) ComplementReact(opts *option.ReactOptions) error {
+	return pt.complement(&opts.Options)
+}
+
+func (pt *Platform) ComplementList(opts *option.ListOptions) error {
+	return pt.complement(&opts.Options)
+}
+
 func (pt *Platform) CI() string {
 	if pt.platform == nil {
 		return ""
@@ -120,6 +154,9 @@ func Get() *Platform {
 	cienv.Add(func(param *cienv.Param) cienv.Platform {
 		return NewGoogleCloudBuild(param)
 	})
+	cienv.Add(func(param *cienv.Param) cienv.Platform {
+		return NewBitbucketPipelines(param)
+	})
 	return &Platform{
 		platform: cienv.Get(nil),
 	}
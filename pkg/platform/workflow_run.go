@@ -0,0 +1,60 @@
+package platform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// workflowRun is the triggering workflow run of the workflow_run event.
+type workflowRun struct {
+	HeadSHA      string `json:"head_sha"`
+	PullRequests []struct {
+		Number int `json:"number"`
+	} `json:"pull_requests"`
+}
+
+// PRNumber returns the pull request number of the triggering workflow run.
+// If the workflow run isn't associated with any pull request, 0 is returned.
+// For instance, pull_requests is empty if the pull request is created from a fork.
+func (run *workflowRun) PRNumber() int {
+	if len(run.PullRequests) == 0 {
+		return 0
+	}
+	return run.PullRequests[0].Number
+}
+
+func parseWorkflowRunEvent(r io.Reader) (*workflowRun, error) {
+	event := struct {
+		WorkflowRun *workflowRun `json:"workflow_run"`
+	}{}
+	if err := json.NewDecoder(r).Decode(&event); err != nil {
+		return nil, fmt.Errorf("parse a workflow_run event payload as JSON: %w", err)
+	}
+	if event.WorkflowRun == nil {
+		return nil, errors.New("workflow_run isn't found in the event payload")
+	}
+	return event.WorkflowRun, nil
+}
+
+// getWorkflowRun reads the triggering workflow run from GITHUB_EVENT_PATH.
+// In workflow_run events, GITHUB_SHA is the commit of the default branch,
+// so the head sha of the triggering workflow run should be used to find the pull request.
+// If the event isn't workflow_run, nil is returned.
+func getWorkflowRun() (*workflowRun, error) {
+	if os.Getenv("GITHUB_EVENT_NAME") != "workflow_run" {
+		return nil, nil //nolint:nilnil
+	}
+	p := os.Getenv("GITHUB_EVENT_PATH")
+	if p == "" {
+		return nil, nil //nolint:nilnil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("open the event payload: %w", err)
+	}
+	defer f.Close()
+	return parseWorkflowRunEvent(f)
+}
@@ -0,0 +1,19 @@
+package platform
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseWorkflowRunEvent(t *testing.T) {
+	t.Parallel()
+	f, err := os.Open("testdata/workflow_run.json")
+	require.Nil(t, err)
+	defer f.Close()
+	run, err := parseWorkflowRunEvent(f)
+	require.Nil(t, err)
+	require.Equal(t, "0123456789abcdef0123456789abcdef01234567", run.HeadSHA)
+	require.Equal(t, 5, run.PRNumber())
+}
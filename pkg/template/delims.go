@@ -0,0 +1,26 @@
+package template
+
+import (
+	"errors"
+	"strings"
+)
+
+// ParseDelims parses a pair of template delimiters such as "<< >>".
+// The pair must be two space-separated tokens.
+func ParseDelims(s string) (string, string, error) {
+	tokens := strings.Fields(s)
+	if len(tokens) != 2 { //nolint:gomnd
+		return "", "", errors.New(`delimiters must be two space-separated tokens such as "<< >>"`)
+	}
+	if tokens[0] == tokens[1] {
+		return "", "", errors.New("the left and right delimiters must be different")
+	}
+	return tokens[0], tokens[1], nil
+}
+
+// ReplaceDelims replaces the default delimiters "{{" and "}}" of a template with custom delimiters.
+// It's used for built-in templates which are written with the default delimiters
+// but are parsed with custom delimiters.
+func ReplaceDelims(tpl, left, right string) string {
+	return strings.NewReplacer("{{", left, "}}", right).Replace(tpl)
+}
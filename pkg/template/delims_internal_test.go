@@ -0,0 +1,106 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDelims(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		s     string
+		left  string
+		right string
+		isErr bool
+	}{
+		{
+			title: "normal",
+			s:     "<< >>",
+			left:  "<<",
+			right: ">>",
+		},
+		{
+			title: "one token",
+			s:     "<<>>",
+			isErr: true,
+		},
+		{
+			title: "same tokens",
+			s:     "%% %%",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			left, right, err := ParseDelims(d.s)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.left, left)
+			require.Equal(t, d.right, right)
+		})
+	}
+}
+
+func TestRenderer_Render_delims(t *testing.T) {
+	t.Parallel()
+	renderer := &Renderer{
+		LeftDelim:  "<<",
+		RightDelim: ">>",
+	}
+	body, err := renderer.Render(`<<template "status" .>> {{ .Values.foo }} <<.Name>>`, map[string]string{
+		"status": `{{.Name}}:`,
+	}, map[string]interface{}{
+		"Name": "foo",
+	})
+	require.Nil(t, err)
+	require.Equal(t, "foo: {{ .Values.foo }} foo", body)
+}
+
+func TestRenderer_Render_delimsUserDefinedTemplates(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		tpl       string
+		templates map[string]string
+		exp       string
+	}{
+		{
+			title: "user-defined template",
+			tpl:   `<<template "foo" .>>`,
+			templates: map[string]string{
+				"foo": `<<.Name>> {{ .Values.foo }}`,
+			},
+			exp: "foo {{ .Values.foo }}",
+		},
+		{
+			title: "user-defined template overriding a built-in template",
+			tpl:   `<<template "status" .>>`,
+			templates: map[string]string{
+				"status": `<<.Name>> {{ .Values.foo }}`,
+			},
+			exp: "foo {{ .Values.foo }}",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			renderer := &Renderer{
+				LeftDelim:  "<<",
+				RightDelim: ">>",
+			}
+			body, err := renderer.Render(d.tpl, d.templates, map[string]interface{}{
+				"Name": "foo",
+			})
+			require.Nil(t, err)
+			require.Equal(t, d.exp, body)
+		})
+	}
+}
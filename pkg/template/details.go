@@ -0,0 +1,35 @@
+package template
+
+import (
+	"html/template"
+	"strings"
+)
+
+// details is the template function which returns a collapsible section.
+// The summary is HTML-escaped and the body is put in a fenced code block.
+// The fence is longer than any sequence of backticks in the body so that the body can't close the code block.
+func details(summary, body string) template.HTML {
+	fence := strings.Repeat("`", maxBacktickRun(body)+1)
+	if len(fence) < 3 { //nolint:gomnd
+		fence = "```"
+	}
+	return template.HTML("<details><summary>" + template.HTMLEscapeString(summary) + "</summary>\n\n" + //nolint:gosec
+		fence + "\n" + strings.TrimSuffix(body, "\n") + "\n" + fence + "\n\n</details>")
+}
+
+// maxBacktickRun returns the length of the longest sequence of backticks in s.
+func maxBacktickRun(s string) int {
+	maxRun := 0
+	run := 0
+	for _, c := range s {
+		if c != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > maxRun {
+			maxRun = run
+		}
+	}
+	return maxRun
+}
@@ -0,0 +1,50 @@
+package template
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_details(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		summary string
+		body    string
+		exp     template.HTML
+	}{
+		{
+			title:   "normal",
+			summary: "Output",
+			body:    "hello\n",
+			exp:     "<details><summary>Output</summary>\n\n```\nhello\n```\n\n</details>",
+		},
+		{
+			title:   "the summary is escaped",
+			summary: "<b>a & b</b>",
+			body:    "<b>hello</b>",
+			exp:     "<details><summary>&lt;b&gt;a &amp; b&lt;/b&gt;</summary>\n\n```\n<b>hello</b>\n```\n\n</details>",
+		},
+		{
+			title:   "the body contains a fence",
+			summary: "Output",
+			body:    "```\nfoo\n```",
+			exp:     "<details><summary>Output</summary>\n\n````\n```\nfoo\n```\n````\n\n</details>",
+		},
+		{
+			title:   "the body contains a long sequence of backticks",
+			summary: "Output",
+			body:    "`inline` and `````",
+			exp:     "<details><summary>Output</summary>\n\n``````\n`inline` and `````\n``````\n\n</details>",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, details(d.summary, d.body))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_diff(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		oldText string
+		newText string
+		exp     string
+	}{
+		{
+			title:   "same",
+			oldText: "foo\nbar",
+			newText: "foo\nbar",
+		},
+		{
+			title:   "changed",
+			oldText: "foo\nbar\nbaz",
+			newText: "foo\nqux\nbaz\nquux",
+			exp:     " foo\n-bar\n+qux\n baz\n+quux",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, diff(d.oldText, d.newText))
+		})
+	}
+}
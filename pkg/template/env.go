@@ -0,0 +1,44 @@
+package template
+
+import (
+	"strings"
+)
+
+// env returns a snapshot of environment variables which are allowed by EnvAllowlist.
+// If a variable is duplicated, the last value is used.
+func (renderer *Renderer) env() map[string]string {
+	return renderer.envPrefix("")
+}
+
+// envPrefix returns environment variables whose names start with prefix and are allowed by EnvAllowlist.
+func (renderer *Renderer) envPrefix(prefix string) map[string]string {
+	m := map[string]string{}
+	if renderer.Environ == nil || len(renderer.EnvAllowlist) == 0 {
+		return m
+	}
+	for _, kv := range renderer.Environ() {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, prefix) || !allowEnv(k, renderer.EnvAllowlist) {
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// allowEnv returns true if name matches with allowlist.
+// A pattern ending with "*" matches names with the prefix.
+func allowEnv(name string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+			continue
+		}
+		if name == pattern {
+			return true
+		}
+	}
+	return false
+}
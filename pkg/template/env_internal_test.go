@@ -0,0 +1,53 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_envPrefix(t *testing.T) {
+	t.Parallel()
+	environ := func() []string {
+		return []string{"CI_JOB=test", "CI_TOKEN=secret", "HOME=/root", "CI_JOB=build"}
+	}
+	data := []struct {
+		title     string
+		allowlist []string
+		prefix    string
+		exp       map[string]string
+	}{
+		{
+			title:  "allowlist is empty",
+			prefix: "CI_",
+			exp:    map[string]string{},
+		},
+		{
+			title:     "only allowed variables are returned",
+			allowlist: []string{"CI_JOB", "HOME"},
+			prefix:    "CI_",
+			exp: map[string]string{
+				"CI_JOB": "build",
+			},
+		},
+		{
+			title:     "wildcard",
+			allowlist: []string{"CI_*"},
+			exp: map[string]string{
+				"CI_JOB":   "build",
+				"CI_TOKEN": "secret",
+			},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			renderer := &Renderer{
+				Environ:      environ,
+				EnvAllowlist: d.allowlist,
+			}
+			require.Equal(t, d.exp, renderer.envPrefix(d.prefix))
+		})
+	}
+}
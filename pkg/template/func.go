@@ -0,0 +1,23 @@
+package template
+
+import (
+	"html/template"
+)
+
+// TemplateFuncProvider provides custom template functions.
+// Library users can implement it to extend Renderer with project specific functions without forking.
+type TemplateFuncProvider interface { //nolint:revive
+	TemplateFuncs() template.FuncMap
+}
+
+// Register adds template functions of the provider to the renderer.
+// Functions registered later take precedence, and custom functions take precedence over built-in functions.
+func (renderer *Renderer) Register(provider TemplateFuncProvider) {
+	funcs := provider.TemplateFuncs()
+	if renderer.Funcs == nil {
+		renderer.Funcs = make(template.FuncMap, len(funcs))
+	}
+	for k, v := range funcs {
+		renderer.Funcs[k] = v
+	}
+}
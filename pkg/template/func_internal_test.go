@@ -0,0 +1,28 @@
+package template
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type upperProvider struct{}
+
+func (p *upperProvider) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"shout": func(s string) string {
+			return strings.ToUpper(s) + "!"
+		},
+	}
+}
+
+func TestRenderer_Register(t *testing.T) {
+	t.Parallel()
+	renderer := &Renderer{}
+	renderer.Register(&upperProvider{})
+	body, err := renderer.Render(`{{shout "hello"}}`, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, "HELLO!", body)
+}
@@ -0,0 +1,30 @@
+package template
+
+import (
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// shieldsEscaper escapes a label and a message of a shields.io static badge.
+// https://shields.io/badges/static-badge
+var shieldsEscaper = strings.NewReplacer("-", "--", "_", "__", " ", "_") //nolint:gochecknoglobals
+
+// altEscaper escapes characters which break the alt text of a markdown image.
+var altEscaper = strings.NewReplacer("[", `\[`, "]", `\]`) //nolint:gochecknoglobals
+
+// badge is the template function which returns a markdown image of a shields.io style badge.
+func badge(label, message, color string) template.HTML {
+	u := "https://img.shields.io/badge/" +
+		url.PathEscape(shieldsEscaper.Replace(label)) + "-" +
+		url.PathEscape(shieldsEscaper.Replace(message)) + "-" +
+		url.PathEscape(color)
+	return img(u, label+": "+message)
+}
+
+// img is the template function which returns a markdown image.
+// The alt text is HTML-escaped, so the result isn't escaped again by html/template.
+func img(u, alt string) template.HTML {
+	u = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+	return template.HTML("![" + altEscaper.Replace(template.HTMLEscapeString(alt)) + "](" + template.HTMLEscapeString(u) + ")") //nolint:gosec
+}
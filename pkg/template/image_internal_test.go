@@ -0,0 +1,71 @@
+package template
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_badge(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		label   string
+		message string
+		color   string
+		exp     template.HTML
+	}{
+		{
+			title:   "normal",
+			label:   "build",
+			message: "passing",
+			color:   "green",
+			exp:     "![build: passing](https://img.shields.io/badge/build-passing-green)",
+		},
+		{
+			title:   "escape dashes, underscores, and spaces",
+			label:   "code coverage",
+			message: "80%-90_%",
+			color:   "yellow",
+			exp:     "![code coverage: 80%-90_%](https://img.shields.io/badge/code_coverage-80%25--90__%25-yellow)",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, badge(d.label, d.message, d.color))
+		})
+	}
+}
+
+func Test_img(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		url   string
+		alt   string
+		exp   template.HTML
+	}{
+		{
+			title: "normal",
+			url:   "https://example.com/a.png",
+			alt:   "graph",
+			exp:   "![graph](https://example.com/a.png)",
+		},
+		{
+			title: "escape",
+			url:   "https://example.com/a (1).png",
+			alt:   "[graph]",
+			exp:   `![\[graph\]](https://example.com/a%20%281%29.png)`,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, img(d.url, d.alt))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package template
+
+import (
+	"math"
+	"strconv"
+)
+
+// mathFuncs returns template functions for arithmetic.
+// sprig's add, sub, mul, and div are kept as is for compatibility, so the functions have the prefix "num".
+// They accept integers, floats, and numeric strings.
+// If all arguments are integers, the result is an integer. Otherwise the result is a float.
+// Non numeric values are treated as 0, and division by zero returns 0.
+func mathFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"numAdd":  add,
+		"numSub":  sub,
+		"numMul":  mul,
+		"numDiv":  div,
+		"percent": percent,
+	}
+}
+
+type number struct {
+	i     int64
+	f     float64
+	isInt bool
+}
+
+func toNumber(v interface{}) number { //nolint:cyclop
+	switch a := v.(type) {
+	case int:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case int8:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case int16:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case int32:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case int64:
+		return number{i: a, f: float64(a), isInt: true}
+	case uint:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case uint8:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case uint16:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case uint32:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case uint64:
+		return number{i: int64(a), f: float64(a), isInt: true}
+	case float32:
+		return number{f: float64(a)}
+	case float64:
+		return number{f: a}
+	case string:
+		if i, err := strconv.ParseInt(a, 10, 64); err == nil {
+			return number{i: i, f: float64(i), isInt: true}
+		}
+		if f, err := strconv.ParseFloat(a, 64); err == nil {
+			return number{f: f}
+		}
+	}
+	return number{isInt: true}
+}
+
+func add(a interface{}, b ...interface{}) interface{} {
+	ret := toNumber(a)
+	for _, v := range b {
+		n := toNumber(v)
+		ret = number{i: ret.i + n.i, f: ret.f + n.f, isInt: ret.isInt && n.isInt}
+	}
+	return ret.value()
+}
+
+func sub(a, b interface{}) interface{} {
+	x, y := toNumber(a), toNumber(b)
+	return number{i: x.i - y.i, f: x.f - y.f, isInt: x.isInt && y.isInt}.value()
+}
+
+func mul(a interface{}, b ...interface{}) interface{} {
+	ret := toNumber(a)
+	for _, v := range b {
+		n := toNumber(v)
+		ret = number{i: ret.i * n.i, f: ret.f * n.f, isInt: ret.isInt && n.isInt}
+	}
+	return ret.value()
+}
+
+func div(a, b interface{}) interface{} {
+	x, y := toNumber(a), toNumber(b)
+	if x.isInt && y.isInt {
+		if y.i == 0 {
+			return int64(0)
+		}
+		return x.i / y.i
+	}
+	if y.f == 0 {
+		return float64(0)
+	}
+	return x.f / y.f
+}
+
+// percent returns a / b * 100 rounded to two decimal places.
+func percent(a, b interface{}) float64 {
+	x, y := toNumber(a), toNumber(b)
+	if y.f == 0 {
+		return 0
+	}
+	return math.Round(x.f/y.f*10000) / 100 //nolint:gomnd
+}
+
+func (n number) value() interface{} {
+	if n.isInt {
+		return n.i
+	}
+	return n.f
+}
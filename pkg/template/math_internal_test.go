@@ -0,0 +1,78 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mathFuncs(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		f     func() interface{}
+		exp   interface{}
+	}{
+		{
+			title: "add integers",
+			f:     func() interface{} { return add(1, 2, "3") },
+			exp:   int64(6),
+		},
+		{
+			title: "add floats",
+			f:     func() interface{} { return add(1, 0.5) },
+			exp:   1.5,
+		},
+		{
+			title: "sub",
+			f:     func() interface{} { return sub("10", 3) },
+			exp:   int64(7),
+		},
+		{
+			title: "mul",
+			f:     func() interface{} { return mul(2, 3, 4) },
+			exp:   int64(24),
+		},
+		{
+			title: "div",
+			f:     func() interface{} { return div(7, 2) },
+			exp:   int64(3),
+		},
+		{
+			title: "div by zero",
+			f:     func() interface{} { return div(7, 0) },
+			exp:   int64(0),
+		},
+		{
+			title: "div floats",
+			f:     func() interface{} { return div(7, 2.0) },
+			exp:   3.5,
+		},
+		{
+			title: "percent",
+			f:     func() interface{} { return percent(1, 3) },
+			exp:   33.33,
+		},
+		{
+			title: "percent of zero",
+			f:     func() interface{} { return percent(1, 0) },
+			exp:   float64(0),
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, d.f())
+		})
+	}
+}
+
+func Test_mathFuncs_keepSprig(t *testing.T) {
+	t.Parallel()
+	funcs := mathFuncs()
+	for _, name := range []string{"add", "sub", "mul", "div"} {
+		_, ok := funcs[name]
+		require.False(t, ok, "sprig's "+name+" must not be overridden")
+	}
+}
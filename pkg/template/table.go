@@ -0,0 +1,60 @@
+package template
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// csvTable is the template function which converts CSV to a markdown table.
+// The first row is treated as the header.
+// Pipes and newlines in cells are escaped so that they don't break the table.
+// Cells are HTML-escaped, so the result isn't escaped again by html/template.
+func csvTable(s string) (template.HTML, error) {
+	reader := csv.NewReader(strings.NewReader(s))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return "", nil
+	}
+	width := 0
+	for _, record := range records {
+		if len(record) > width {
+			width = len(record)
+		}
+	}
+	buf := &strings.Builder{}
+	writeTableRow(buf, records[0], width)
+	buf.WriteString("|")
+	for i := 0; i < width; i++ {
+		buf.WriteString(" --- |")
+	}
+	buf.WriteString("\n")
+	for _, record := range records[1:] {
+		writeTableRow(buf, record, width)
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec
+}
+
+func writeTableRow(buf *strings.Builder, record []string, width int) {
+	buf.WriteString("|")
+	for i := 0; i < width; i++ {
+		cell := ""
+		if i < len(record) {
+			cell = escapeTableCell(record[i])
+		}
+		buf.WriteString(" " + cell + " |")
+	}
+	buf.WriteString("\n")
+}
+
+func escapeTableCell(cell string) string {
+	cell = template.HTMLEscapeString(cell)
+	cell = strings.ReplaceAll(cell, "|", `\|`)
+	cell = strings.ReplaceAll(cell, "\r\n", "<br>")
+	return strings.ReplaceAll(cell, "\n", "<br>")
+}
@@ -0,0 +1,55 @@
+package template
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_csvTable(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		csv   string
+		exp   template.HTML
+		isErr bool
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "normal",
+			csv:   "name,count\nfoo,1\nbar,2\n",
+			exp:   "| name | count |\n| --- | --- |\n| foo | 1 |\n| bar | 2 |\n",
+		},
+		{
+			title: "escape pipes and newlines",
+			csv:   "name,value\n\"a|b\",\"c\nd\"\n",
+			exp:   "| name | value |\n| --- | --- |\n| a\\|b | c<br>d |\n",
+		},
+		{
+			title: "rows with fewer fields",
+			csv:   "a,b\n1\n",
+			exp:   "| a | b |\n| --- | --- |\n| 1 |  |\n",
+		},
+		{
+			title: "invalid CSV",
+			csv:   "a,\"b\n",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			table, err := csvTable(d.csv)
+			if d.isErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, d.exp, table)
+		})
+	}
+}
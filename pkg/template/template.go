@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/suzuki-shunsuke/github-comment/pkg/expr"
 )
 
 type ParamGetTemplates struct {
@@ -15,6 +16,36 @@ type ParamGetTemplates struct {
 	CI             string
 	JoinCommand    string
 	CombinedOutput string
+	// Lang is the language of built-in templates. The default is English
+	Lang string
+}
+
+// labels are labels of built-in templates by language.
+// If a label isn't found, the English label is used.
+var labels = map[string]map[string]string{ //nolint:gochecknoglobals
+	"en": {
+		"workflow":   "workflow",
+		"job":        "job",
+		"build_link": "Build link",
+		"build":      "build",
+		"step":       "step",
+		"env":        "Environment variables",
+	},
+	"ja": {
+		"workflow":   "ワークフロー",
+		"job":        "ジョブ",
+		"build_link": "ビルドリンク",
+		"build":      "ビルド",
+		"step":       "ステップ",
+		"env":        "環境変数",
+	},
+}
+
+func getLabel(lang, key string) string {
+	if label, ok := labels[lang][key]; ok {
+		return label
+	}
+	return labels["en"][key]
 }
 
 func GetTemplates(param *ParamGetTemplates) map[string]string {
@@ -24,25 +55,37 @@ func GetTemplates(param *ParamGetTemplates) map[string]string {
 	}
 	buildLinks := map[string]string{
 		"circleci": fmt.Sprintf(
-			`[workflow](https://circleci.com/workflow-run/%s) [job](%s) (job: %s)`,
+			`[%s](https://circleci.com/workflow-run/%s) [%s](%s) (%s: %s)`,
+			getLabel(param.Lang, "workflow"),
 			os.Getenv("CIRCLE_WORKFLOW_ID"),
+			getLabel(param.Lang, "job"),
 			os.Getenv("CIRCLE_BUILD_URL"),
+			getLabel(param.Lang, "job"),
 			os.Getenv("CIRCLE_JOB"),
 		),
-		"codebuild": fmt.Sprintf(`[Build link](%s)`, os.Getenv("CODEBUILD_BUILD_URL")),
+		"codebuild": fmt.Sprintf(`[%s](%s)`, getLabel(param.Lang, "build_link"), os.Getenv("CODEBUILD_BUILD_URL")),
 		"drone": fmt.Sprintf(
-			`[build](%s) [step](%s/%s/%s)`,
+			`[%s](%s) [%s](%s/%s/%s)`,
+			getLabel(param.Lang, "build"),
 			os.Getenv("DRONE_BUILD_LINK"),
+			getLabel(param.Lang, "step"),
 			os.Getenv("DRONE_BUILD_LINK"),
 			os.Getenv("DRONE_STAGE_NUMBER"),
 			os.Getenv("DRONE_STEP_NUMBER"),
 		),
 		"github-actions": fmt.Sprintf(
-			`[Build link](%s/%s/actions/runs/%s)`,
+			`[%s](%s/%s/actions/runs/%s)`,
+			getLabel(param.Lang, "build_link"),
 			os.Getenv("GITHUB_SERVER_URL"),
 			os.Getenv("GITHUB_REPOSITORY"),
 			os.Getenv("GITHUB_RUN_ID"),
 		),
+		"bitbucket-pipelines": fmt.Sprintf(
+			`[%s](%s/addon/pipelines/home#!/results/%s)`,
+			getLabel(param.Lang, "build_link"),
+			os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN"),
+			os.Getenv("BITBUCKET_BUILD_NUMBER"),
+		),
 		"cloud-build": fmt.Sprintf(
 			"https://console.cloud.google.com/cloud-build/builds;region=%s/%s?project=%s",
 			cloudBuildRegion,
@@ -55,6 +98,7 @@ func GetTemplates(param *ParamGetTemplates) map[string]string {
 		"status":                 `:{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}:`,
 		"join_command":           "```\n$ {{.JoinCommand | AvoidHTMLEscape}}\n```",
 		"hidden_combined_output": "<details>\n\n```\n{{.CombinedOutput | AvoidHTMLEscape}}\n```\n\n</details>",
+		"hidden_command_env":     "{{if .Command.Env}}<details><summary>" + getLabel(param.Lang, "env") + "</summary>\n\n```\n{{range $k, $v := .Command.Env}}{{$k}}={{$v | AvoidHTMLEscape}}\n{{end}}```\n\n</details>{{end}}",
 	}
 	if strings.Contains(param.JoinCommand, "```") {
 		builtinTemplates["join_command"] = "<pre><code>$ {{.JoinCommand | AvoidHTMLEscape}}</pre></code>"
@@ -82,6 +126,39 @@ func GetTemplates(param *ParamGetTemplates) map[string]string {
 
 type Renderer struct {
 	Getenv func(string) string
+	// GHEBaseURL is used to build URLs of GitHub Enterprise Server
+	GHEBaseURL string
+	// Funcs are custom template functions.
+	// They take precedence over built-in functions
+	Funcs template.FuncMap
+	// LeftDelim and RightDelim are delimiters of comment templates and user-defined templates.
+	// If they are empty, "{{" and "}}" are used.
+	// Built-in templates are parsed with the default delimiters
+	LeftDelim  string
+	RightDelim string
+	// Environ returns environment variables in the form "key=value". os.Environ
+	Environ func() []string
+	// EnvAllowlist is names of environment variables which the template functions env and envPrefix can return.
+	// The caller is responsible for excluding secrets
+	EnvAllowlist []string
+}
+
+// builtinTemplateNames are names of built-in templates returned by GetTemplates.
+var builtinTemplateNames = map[string]struct{}{ //nolint:gochecknoglobals
+	"link":                   {},
+	"status":                 {},
+	"join_command":           {},
+	"hidden_combined_output": {},
+	"hidden_command_env":     {},
+}
+
+// isBuiltinTemplate returns true if the named template should be parsed as a built-in template with the default delimiters.
+// A user-defined template which overrides a built-in template is parsed with the custom delimiters if it includes the custom left delimiter.
+func isBuiltinTemplate(name, tpl, leftDelim string) bool {
+	if _, ok := builtinTemplateNames[name]; !ok {
+		return false
+	}
+	return !strings.Contains(tpl, leftDelim)
 }
 
 func addTemplates(tpl string, templates map[string]string) string {
@@ -96,17 +173,48 @@ func avoidHTMLEscape(text string) template.HTML {
 }
 
 func (renderer *Renderer) Render(tpl string, templates map[string]string, params interface{}) (string, error) {
-	tpl = addTemplates(tpl, templates)
+	customDelims := renderer.LeftDelim != "" || renderer.RightDelim != ""
+	if !customDelims {
+		tpl = addTemplates(tpl, templates)
+	}
 
 	// delete some functions for security reason
 	funcs := sprig.FuncMap()
 	delete(funcs, "env")
 	delete(funcs, "expandenv")
 	delete(funcs, "getHostByName")
-	tmpl, err := template.New("comment").Funcs(template.FuncMap{
+	// arithmetic functions which handle floats, numeric strings, and division by zero
+	for k, v := range mathFuncs() {
+		funcs[k] = v
+	}
+	tmpl := template.New("comment").Funcs(template.FuncMap{
 		"Env":             renderer.Getenv,
 		"AvoidHTMLEscape": avoidHTMLEscape,
-	}).Funcs(funcs).Parse(tpl)
+		"countMatches":    expr.CountMatches,
+		"commentURL":      renderer.commentURL,
+		"csvTable":        csvTable,
+		"badge":           badge,
+		"img":             img,
+		"diff":            diff,
+		"truncateBytes":   truncateBytes,
+		"details":         details,
+		"env":             renderer.env,
+		"envPrefix":       renderer.envPrefix,
+	}).Funcs(funcs).Funcs(renderer.Funcs)
+	if customDelims {
+		tmpl = tmpl.Delims(renderer.LeftDelim, renderer.RightDelim)
+		for k, v := range templates {
+			t := tmpl.New(k)
+			if isBuiltinTemplate(k, v, renderer.LeftDelim) {
+				// built-in templates are written with the default delimiters
+				t = t.Delims("", "")
+			}
+			if _, err := t.Parse(v); err != nil {
+				return "", fmt.Errorf("parse a template %s: %w", k, err)
+			}
+		}
+	}
+	tmpl, err := tmpl.Parse(tpl)
 	if err != nil {
 		return "", fmt.Errorf("parse a template: %w", err)
 	}
@@ -0,0 +1,42 @@
+package template
+
+import "unicode/utf8"
+
+const truncatedBytesMarker = "\n... (truncated)"
+
+// truncateBytes is the template function which truncates s to at most n bytes including the marker.
+// The arguments are in the same order as sprig's trunc so that it can be used in pipelines.
+// s is truncated on a rune boundary and an ANSI escape sequence isn't split.
+func truncateBytes(n int, s string) string {
+	if len(s) <= n {
+		return s
+	}
+	idx := n - len(truncatedBytesMarker)
+	if idx < 0 {
+		idx = 0
+	}
+	for idx > 0 && !utf8.RuneStart(s[idx]) {
+		idx--
+	}
+	return s[:ansiSequenceStart(s[:idx])] + truncatedBytesMarker
+}
+
+// ansiSequenceStart returns the index of the ANSI escape sequence which isn't terminated at the end of s.
+// If the escape sequence at the end of s is terminated or there is no escape sequence, len(s) is returned.
+func ansiSequenceStart(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == '\x1b' {
+			return i
+		}
+		// A CSI sequence consists of parameter bytes (0x30-0x3F) and intermediate bytes (0x20-0x2F)
+		// and is terminated by a final byte (0x40-0x7E). "[" is the start of a CSI sequence
+		if c == '[' && i > 0 && s[i-1] == '\x1b' {
+			continue
+		}
+		if c < 0x20 || c > 0x3f {
+			return len(s)
+		}
+	}
+	return len(s)
+}
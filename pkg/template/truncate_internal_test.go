@@ -0,0 +1,62 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_truncateBytes(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		s     string
+		n     int
+		exp   string
+	}{
+		{
+			title: "not truncated",
+			s:     "hello",
+			n:     5,
+			exp:   "hello",
+		},
+		{
+			title: "truncated",
+			s:     strings.Repeat("a", 100),
+			n:     len(truncatedBytesMarker) + 3,
+			exp:   "aaa" + truncatedBytesMarker,
+		},
+		{
+			title: "a multibyte character isn't split",
+			s:     strings.Repeat("あ", 100),
+			n:     len(truncatedBytesMarker) + 4,
+			exp:   "あ" + truncatedBytesMarker,
+		},
+		{
+			title: "an escape sequence isn't split",
+			s:     "ab\x1b[31mred" + strings.Repeat("x", 20),
+			n:     len(truncatedBytesMarker) + 5,
+			exp:   "ab" + truncatedBytesMarker,
+		},
+		{
+			title: "a terminated escape sequence is kept",
+			s:     "ab\x1b[31mred" + strings.Repeat("x", 20),
+			n:     len(truncatedBytesMarker) + 8,
+			exp:   "ab\x1b[31mr" + truncatedBytesMarker,
+		},
+		{
+			title: "n is less than the marker",
+			s:     "hello",
+			n:     1,
+			exp:   truncatedBytesMarker,
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, d.exp, truncateBytes(d.n, d.s))
+		})
+	}
+}
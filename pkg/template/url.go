@@ -0,0 +1,34 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// webBaseURL returns the base URL of the GitHub web UI.
+// GHEBaseURL is the API endpoint such as https://ghe.example.com/api/v3/ ,
+// so the path of the API is removed.
+func webBaseURL(gheBaseURL string) string {
+	if gheBaseURL == "" {
+		return "https://github.com"
+	}
+	u := strings.TrimSuffix(gheBaseURL, "/")
+	u = strings.TrimSuffix(u, "/api/v3")
+	return strings.TrimSuffix(u, "/")
+}
+
+func toInt64(v interface{}) int64 {
+	n := toNumber(v)
+	if n.isInt {
+		return n.i
+	}
+	return int64(n.f)
+}
+
+// commentURL is the template function which returns the URL of the pull request comment.
+// The URL respects GHEBaseURL, so it works with GitHub Enterprise Server.
+func (renderer *Renderer) commentURL(org, repo string, prNumber, commentID interface{}) string {
+	return fmt.Sprintf(
+		"%s/%s/%s/pull/%d#issuecomment-%d",
+		webBaseURL(renderer.GHEBaseURL), org, repo, toInt64(prNumber), toInt64(commentID))
+}
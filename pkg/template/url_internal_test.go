@@ -0,0 +1,39 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_commentURL(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title      string
+		gheBaseURL string
+		prNumber   interface{}
+		exp        string
+	}{
+		{
+			title:    "github.com",
+			prNumber: 1,
+			exp:      "https://github.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-100",
+		},
+		{
+			title:      "GitHub Enterprise Server",
+			gheBaseURL: "https://ghe.example.com/api/v3/",
+			prNumber:   "1",
+			exp:        "https://ghe.example.com/suzuki-shunsuke/github-comment/pull/1#issuecomment-100",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			renderer := &Renderer{
+				GHEBaseURL: d.gheBaseURL,
+			}
+			require.Equal(t, d.exp, renderer.commentURL("suzuki-shunsuke", "github-comment", d.prNumber, int64(100)))
+		})
+	}
+}